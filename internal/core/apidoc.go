@@ -0,0 +1,54 @@
+package core
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// findAPIDocLinks 从 springfox 分组列表、springdoc 配置和 Swagger UI 初始化代码中获取 API 文档的地址。
+// 文档与探测的路径一样从深度 1 开始请求，由 Swagger 文档的处理流程解析
+func (runner *Runner) findAPIDocLinks(r *colly.Response) {
+	var (
+		links []string
+		err   error
+		f     string
+	)
+	urlPath := r.Request.URL.Path
+	isHTML := r.Headers != nil && strings.Contains(r.Headers.Get("Content-Type"), "html")
+	switch name := path.Base(urlPath); {
+	case isHTML:
+		// 旧版本的 Swagger UI 在页面的内联脚本中初始化
+		links, f = finder.FindLinksFromSwaggerUI(string(r.Body)), output.FinderSwaggerUI
+	case name == "swagger-resources":
+		f = output.FinderSwaggerRes
+		links, err = finder.FindLinksFromSwaggerResources(r.Body)
+		// springfox 返回的地址相对于应用的 context path
+		base := strings.TrimSuffix(urlPath, "/swagger-resources")
+		for i, link := range links {
+			if strings.HasPrefix(link, "/") && !strings.HasPrefix(link, base+"/") {
+				links[i] = base + link
+			}
+		}
+	case strings.Contains(name, "swagger-config"):
+		f = output.FinderSwaggerCfg
+		links, err = finder.FindLinksFromSwaggerConfig(r.Body)
+	case name == "swagger-initializer.js":
+		links, f = finder.FindLinksFromSwaggerUI(string(r.Body)), output.FinderSwaggerUI
+	default:
+		return
+	}
+	if err != nil {
+		log.WithField("error", err).Debug("Parse API document config error: ", r.Request.URL.String())
+		return
+	}
+
+	for _, link := range links {
+		log.Debug("Found API document: ", link)
+		runner.requestFile(r.Request.AbsoluteURL(link), r.Request, output.Provenance{Source: output.SourceSwagger, Finder: f, Raw: link})
+	}
+}
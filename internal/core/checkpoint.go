@@ -0,0 +1,180 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// pendingRequest 是已经发出但尚未完成的请求，字段名与 colly 序列化请求的格式一致，
+// 可以直接通过 Collector.UnmarshalRequest 还原
+type pendingRequest struct {
+	URL     string
+	Method  string
+	Depth   int
+	Body    []byte            `json:",omitempty"`
+	Ctx     map[string]string `json:",omitempty"`
+	Headers http.Header       `json:",omitempty"`
+}
+
+// checkpoint 保存爬取状态，用于中断后继续爬取
+type checkpoint struct {
+	Targets      []string          `json:"targets"`
+	Time         time.Time         `json:"time"`
+	Visited      []string          `json:"visited"`
+	Pending      []*pendingRequest `json:"pending"`
+	Cursor       int               `json:"cursor"` // 最后一个已经发出的字典条目，按 目标序号*字典长度+条目序号 编号
+	ErrorCounter int64             `json:"error_counter"`
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid state file: %w", err)
+	}
+	return &cp, nil
+}
+
+// track 记录新发出的请求，请求完成后通过 untrack 移除
+func (runner *Runner) track(ctx *colly.Context, method, link string, depth int, body []byte, headers http.Header) {
+	if runner.options.StatePath == "" {
+		return
+	}
+
+	values := contextValues(ctx)
+
+	runner.stateMutex.Lock()
+	defer runner.stateMutex.Unlock()
+	runner.pending[ctx] = &pendingRequest{
+		URL:     link,
+		Method:  method,
+		Depth:   depth,
+		Body:    body,
+		Ctx:     values,
+		Headers: headers,
+	}
+}
+
+// contextValues 返回上下文中所有字符串类型的值
+func contextValues(ctx *colly.Context) map[string]string {
+	values := make(map[string]string)
+	ctx.ForEach(func(k string, v interface{}) interface{} {
+		if s, ok := v.(string); ok {
+			values[k] = s
+		}
+		return nil
+	})
+	return values
+}
+
+func (runner *Runner) untrack(ctx *colly.Context) {
+	if runner.options.StatePath == "" {
+		return
+	}
+	runner.stateMutex.Lock()
+	defer runner.stateMutex.Unlock()
+	delete(runner.pending, ctx)
+}
+
+// saveCheckpoint 将当前状态写入状态文件，先写临时文件再重命名，避免中途退出导致文件损坏
+func (runner *Runner) saveCheckpoint() {
+	path := runner.options.StatePath
+	if path == "" {
+		return
+	}
+
+	runner.stateMutex.Lock()
+	cp := &checkpoint{
+		Targets:      runner.options.targetURLs(),
+		Time:         time.Now(),
+		Visited:      runner.urlSet.ToSlice(),
+		Cursor:       runner.cursor,
+		ErrorCounter: atomic.LoadInt64(&runner.errorCounter),
+	}
+	for _, p := range runner.pending {
+		cp.Pending = append(cp.Pending, p)
+	}
+	runner.stateMutex.Unlock()
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		log.Errorf("Save checkpoint error: %s", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		log.Errorf("Save checkpoint error: %s", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Errorf("Save checkpoint error: %s", err)
+		return
+	}
+	log.
+		WithFields(log.Fields{"visited": len(cp.Visited), "pending": len(cp.Pending), "cursor": cp.Cursor}).
+		Debug("Checkpoint saved: ", path)
+}
+
+// autoCheckpoint 定期保存状态，直到 done 被关闭
+func (runner *Runner) autoCheckpoint(done <-chan struct{}) {
+	interval := time.Duration(runner.options.CheckpointInterval) * time.Second
+	if runner.options.StatePath == "" || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			runner.saveCheckpoint()
+		case <-done:
+			return
+		}
+	}
+}
+
+// resume 恢复上次保存的状态：已访问的链接、错误计数、字典位置，并重新发出未完成的请求
+func (runner *Runner) resume(cp *checkpoint) {
+	runner.urlSet.Append(cp.Visited...)
+	atomic.StoreInt64(&runner.errorCounter, cp.ErrorCounter)
+	runner.cursor = cp.Cursor
+
+	log.
+		WithFields(log.Fields{"visited": len(cp.Visited), "pending": len(cp.Pending), "cursor": cp.Cursor}).
+		Info("Resume from checkpoint: ", cp.Time.Format(time.DateTime))
+
+	for _, p := range cp.Pending {
+		data, err := json.Marshal(p)
+		if err != nil {
+			continue
+		}
+		req, err := runner.collector.UnmarshalRequest(data)
+		if err != nil {
+			log.WithField("link", p.URL).Warn("Restore request error: ", err)
+			continue
+		}
+		runner.track(req.Ctx, p.Method, p.URL, p.Depth, p.Body, p.Headers)
+		if err := req.Do(); err != nil {
+			runner.untrack(req.Ctx)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+	"github.com/zrquan/gatherer/pkg/param"
+)
+
+// handleForm 输出页面中的表单并记录其中的参数，设置了 -submit 时使用生成的值提交表单
+func (runner *Runner) handleForm(e *colly.HTMLElement) {
+	form := finder.ParseForm(e.DOM)
+	// 没有 action 的表单提交到当前页面
+	link := e.Request.URL.String()
+	if form.Action != "" {
+		link = e.Request.AbsoluteURL(form.Action)
+	}
+	if link == "" || !runner.inScope(runner.targetOf(e.Request.Ctx), link) {
+		return
+	}
+	if runner.params != nil {
+		runner.params.Add(form.Method, link, param.LocationForm, form.Names()...)
+	}
+
+	// 多个页面中的同一个表单只处理一次
+	names := form.Names()
+	slices.Sort(names)
+	if !runner.forms.Add(form.Method + " " + link + " " + strings.Join(names, ",")) {
+		return
+	}
+	runner.options.writer.Write(newFormResult(link, form, e.Request))
+	if runner.options.SubmitForms {
+		runner.submitForm(link, form, e.Request)
+	}
+}
+
+// submitForm 按表单的方法和编码类型提交表单，GET 表单的值会替换 action 中的查询字符串
+func (runner *Runner) submitForm(link string, form *finder.Form, request *colly.Request) {
+	// 提交登录表单可能导致账号被锁定
+	if form.HasPassword() {
+		log.Debug("Skip submitting form with password field: ", link)
+		return
+	}
+	prov := output.Provenance{Source: output.SourceHTML, Finder: output.FinderFormSubmit, Raw: form.Method + " " + form.Action}
+	if form.Method == "GET" {
+		u, err := url.Parse(link)
+		if err != nil {
+			return
+		}
+		u.RawQuery = form.Values().Encode()
+		runner.visitLink(u.String(), request, prov)
+		return
+	}
+	body, contentType := form.Encode()
+	runner.visitRequest(form.Method, link, body, contentType, request, prov)
+}
+
+func newFormResult(link string, form *finder.Form, request *colly.Request) *output.Result {
+	result := &output.Result{
+		URL:       link,
+		Method:    form.Method,
+		SourceURL: request.URL.String(),
+		Source:    output.SourceHTML,
+		Raw:       form.Action,
+		Depth:     request.Depth,
+		Target:    request.Ctx.Get("target"),
+		Type:      output.TypeForm,
+		Form: &output.Form{
+			Method:  form.Method,
+			Action:  form.Action,
+			Enctype: form.Enctype,
+		},
+	}
+	for _, f := range form.Fields {
+		result.Form.Fields = append(result.Form.Fields, &output.FormField{
+			Name:     f.Name,
+			Type:     f.Type,
+			Value:    f.Value,
+			Options:  f.Options,
+			Required: f.Required,
+			CSRF:     f.CSRF,
+		})
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+	"github.com/zrquan/gatherer/pkg/util"
+)
+
+// 超过该大小的响应不检测是否为 GraphQL 响应
+const maxGraphQLDetectSize = 64 << 10
+
+// graphqlState 记录已经确认的 GraphQL 接口和 JS 代码中的操作，新发现的操作会发送到所有已确认的接口
+type graphqlState struct {
+	endpoints []*colly.Request // 内省查询的请求，作为重放操作的父请求
+	ops       []*finder.GraphQLOperation
+}
+
+// probeGraphQL 向可能的 GraphQL 接口发送内省查询，每个接口只发送一次。只有设置了 -gql 时才发送
+func (runner *Runner) probeGraphQL(link string, request *colly.Request, prov output.Provenance) {
+	if !runner.options.GraphQL {
+		return
+	}
+	link = request.AbsoluteURL(link)
+	link, _, _ = strings.Cut(link, "?")
+	if link == "" || !runner.inScope(runner.targetOf(request.Ctx), link) || !runner.gqlProbed.Add(link) {
+		return
+	}
+	log.Debug("Send introspection query to: ", link)
+	prov.Finder = output.FinderIntrospect
+	runner.sendGraphQL(link, finder.IntrospectionQuery, "IntrospectionQuery", request, prov)
+}
+
+// sendGraphQL 发送 GraphQL 请求，请求方法策略不允许 POST 时使用 GET 请求。与 Swagger 文档中的接口一样从深度 1 开始
+func (runner *Runner) sendGraphQL(link, query, operationName string, request *colly.Request, prov output.Provenance) {
+	method := "POST"
+	op := &finder.GraphQLOperation{Name: operationName, Document: query}
+	body := op.Body()
+	headers := http.Header{"Content-Type": []string{"application/json"}}
+	if p := runner.options.policy; p != nil && !p.Allow("POST") {
+		method, body, headers = "GET", nil, nil
+		link += "?" + url.Values{"query": {query}, "operationName": {operationName}}.Encode()
+	}
+
+	ctx := newContext(request, prov)
+	runner.track(ctx, method, link, 1, body, headers)
+	var data io.Reader
+	if body != nil {
+		data = bytes.NewReader(body)
+	}
+	if err := runner.collector.Request(method, link, data, ctx, headers); err != nil {
+		runner.untrack(ctx)
+	}
+}
+
+// detectGraphQL 根据响应的结构发现 GraphQL 接口
+func (runner *Runner) detectGraphQL(r *colly.Response) {
+	if !runner.options.GraphQL || len(r.Body) == 0 || len(r.Body) > maxGraphQLDetectSize || r.Ctx.Get("source") == string(output.SourceGraphQL) {
+		return
+	}
+	if finder.IsGraphQLResponse(r.Body) {
+		link := r.Request.URL.String()
+		runner.probeGraphQL(link, r.Request, output.Provenance{Source: output.SourceGraphQL, Raw: link})
+	}
+}
+
+// handleIntrospection 解析内省查询的响应，输出 schema 中的所有操作并重放其中的 query。
+// 内省被禁用时，只要响应是 GraphQL 格式就确认该接口，之后使用 JS 代码中的操作访问它
+func (runner *Runner) handleIntrospection(r *colly.Response) {
+	link, _, _ := strings.Cut(r.Request.URL.String(), "?")
+	ops, err := finder.ParseIntrospection(r.Body)
+	if err == nil {
+		log.Infof("Found %d GraphQL operations from introspection: %s", len(ops), link)
+		for _, op := range ops {
+			runner.writeOperation(link, op, r.Request, output.Provenance{Source: output.SourceGraphQL, Finder: output.FinderIntrospect})
+			runner.replayOperation(link, op, r.Request)
+		}
+	} else if !finder.IsGraphQLResponse(r.Body) {
+		return
+	} else {
+		log.Info("GraphQL introspection is disabled: ", link)
+	}
+
+	runner.mutex.Lock()
+	runner.graphql.endpoints = append(runner.graphql.endpoints, r.Request)
+	ops = append([]*finder.GraphQLOperation(nil), runner.graphql.ops...)
+	runner.mutex.Unlock()
+	for _, op := range ops {
+		runner.replayOperation(link, op, r.Request)
+	}
+}
+
+// findGraphQLFromJS 输出 JS 代码中的 GraphQL 操作。设置了 -gql 时还会向其中的 GraphQL 地址发送内省查询，
+// 并将操作发送到已经确认的接口
+func (runner *Runner) findGraphQLFromJS(r *colly.Response, content string) {
+	endpoints, ops := finder.FindGraphQLFromJS(content)
+	for _, ep := range endpoints {
+		link := ep
+		if !util.IsAbsoluteURL(link) {
+			link = runner.resolveEndpoint(r.Request, link)
+		}
+		runner.probeGraphQL(link, r.Request, output.Provenance{Source: output.SourceJS, Raw: ep})
+	}
+
+	for _, op := range ops {
+		runner.mutex.Lock()
+		exists := slices.ContainsFunc(runner.graphql.ops, func(o *finder.GraphQLOperation) bool {
+			return o.Type == op.Type && o.Name == op.Name
+		})
+		if !exists {
+			runner.graphql.ops = append(runner.graphql.ops, op)
+		}
+		requests := append([]*colly.Request(nil), runner.graphql.endpoints...)
+		runner.mutex.Unlock()
+		if exists {
+			continue
+		}
+
+		runner.writeOperation(r.Request.URL.String(), op, r.Request, output.Provenance{Source: output.SourceJS, Finder: output.FinderGraphQL})
+		for _, req := range requests {
+			link, _, _ := strings.Cut(req.URL.String(), "?")
+			runner.replayOperation(link, op, req)
+		}
+	}
+}
+
+// replayOperation 将 query 发送到接口，mutation 会修改数据，subscription 需要 WebSocket，都只输出不发送
+func (runner *Runner) replayOperation(link string, op *finder.GraphQLOperation, request *colly.Request) {
+	if op.Type != finder.GraphQLQuery || op.Document == "" {
+		return
+	}
+	prov := output.Provenance{Source: output.SourceGraphQL, Finder: output.FinderGraphQL, Raw: op.Document}
+	runner.sendGraphQL(link, op.Document, op.Name, request, prov)
+}
+
+func (runner *Runner) writeOperation(link string, op *finder.GraphQLOperation, request *colly.Request, prov output.Provenance) {
+	runner.options.writer.Write(&output.Result{
+		URL:       link,
+		SourceURL: request.URL.String(),
+		Source:    prov.Source,
+		Finder:    prov.Finder,
+		Raw:       op.Document,
+		Depth:     request.Depth,
+		Target:    request.Ctx.Get("target"),
+		Type:      output.TypeGraphQL,
+		GraphQL: &output.GraphQL{
+			Operation:  op.Type,
+			Name:       op.Name,
+			Args:       op.Args,
+			ReturnType: op.ReturnType,
+			Document:   op.Document,
+		},
+	})
+}
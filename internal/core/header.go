@@ -0,0 +1,62 @@
+package core
+
+import (
+	"net/url"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// 各个响应头对应的 finder
+var headerFinders = map[string]string{
+	"Location":                            output.FinderLocation,
+	"Content-Location":                    output.FinderContentLoc,
+	"Link":                                output.FinderLinkHeader,
+	"Refresh":                             output.FinderRefresh,
+	"Content-Security-Policy":             output.FinderCSP,
+	"Content-Security-Policy-Report-Only": output.FinderCSP,
+	"Access-Control-Allow-Origin":         output.FinderCORS,
+}
+
+// findHeaderLinks 访问响应头中作用域内的链接，作用域外的主机作为资产输出
+func (runner *Runner) findHeaderLinks(r *colly.Response) {
+	if r.Headers == nil {
+		return
+	}
+	t := runner.targetOf(r.Ctx)
+	for _, hl := range finder.FindLinksFromHeaders(*r.Headers) {
+		link := r.Request.AbsoluteURL(hl.Link)
+		if link == "" {
+			continue
+		}
+		prov := output.Provenance{Source: output.SourceHeader, Finder: headerFinders[hl.Header], Raw: hl.Raw}
+		if runner.inScope(t, link) {
+			runner.visitLink(link, r.Request, prov)
+			continue
+		}
+		runner.writeAsset(link, r.Request, prov)
+	}
+}
+
+// writeAsset 输出作用域外链接的主机，每个主机只输出一次
+func (runner *Runner) writeAsset(link string, request *colly.Request, prov output.Provenance) {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return
+	}
+	origin := u.Scheme + "://" + u.Host
+	if !runner.assets.Add(origin) {
+		return
+	}
+	runner.options.writer.Write(&output.Result{
+		URL:       origin,
+		SourceURL: request.URL.String(),
+		Source:    prov.Source,
+		Finder:    prov.Finder,
+		Raw:       prov.Raw,
+		Depth:     request.Depth,
+		Target:    request.Ctx.Get("target"),
+		Type:      output.TypeAsset,
+	})
+}
@@ -4,11 +4,17 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"net/url"
+	"slices"
+	"strings"
 
+	"github.com/zrquan/gatherer/pkg/auth"
 	"github.com/zrquan/gatherer/pkg/filter"
+	"github.com/zrquan/gatherer/pkg/finder"
 	"github.com/zrquan/gatherer/pkg/input"
 	"github.com/zrquan/gatherer/pkg/output"
+	"github.com/zrquan/gatherer/pkg/policy"
+	"github.com/zrquan/gatherer/pkg/scope"
+	"github.com/zrquan/gatherer/pkg/secret"
 	"github.com/zrquan/gatherer/pkg/util"
 )
 
@@ -24,39 +30,85 @@ func (h *headerFlag) String() string {
 }
 
 type Options struct {
-	Target          string
-	Depth           int
-	Timeout         int
-	TotalTimeout    int
-	Headers         headerFlag
-	WordlistPath    string
-	Parallel        int
-	Debug           bool
-	RandomUA        bool
-	Proxy           string
-	VisitSubdomains bool
-	NoRedirect      bool
-	UseChrome       bool
-	IgnoreQuery     bool
-	JSONFormat      bool
-	StatusFilter    string
-	ExtensionFilter string
-	LengthFilter    string
-
-	wordlist   *input.Wordlist
-	targetRoot string
-	filters    []filter.IFilter
+	Target             string
+	TargetList         string
+	ScopePath          string
+	NeverVisit         string
+	MethodMode         string
+	AuthPath           string
+	Methods            string
+	Depth              int
+	Timeout            int
+	TotalTimeout       int
+	Headers            headerFlag
+	WordlistPath       string
+	Parallel           int
+	Debug              bool
+	RandomUA           bool
+	Proxy              string
+	VisitSubdomains    bool
+	NoRedirect         bool
+	UseChrome          bool
+	Pages              int
+	SourceMapDir       string
+	SecretScan         bool
+	SubmitForms        bool
+	SitemapLimit       int
+	Polite             bool
+	WellKnown          bool
+	GraphQL            bool
+	APIDoc             bool
+	DisableSources     string
+	SecretRules        string
+	Interact           bool
+	InteractDepth      int
+	InteractActions    int
+	IgnoreQuery        bool
+	JSONFormat         bool
+	StatusFilter       string
+	ExtensionFilter    string
+	LengthFilter       string
+	OutputPath         string
+	OutputFormat       string
+	GraphPath          string
+	ParamsPath         string
+	ParamWordlist      string
+	DedupMode          string
+	DedupThreshold     int
+	StripDynamic       bool
+	WildcardProbes     int
+	StatePath          string
+	CheckpointInterval int
+	Resume             bool
+
+	wordlist *input.Wordlist
+	targets  []*target
+	scope    *scope.Scope
+	policy   *policy.MethodPolicy
+	auth     *auth.Config
+	secrets  *secret.Scanner
+	disabled []string // 禁用的 HTML 链接来源
+	filters  []filter.IFilter
+	writer   *output.MultiWriter
 }
 
 func ParseOptions() (*Options, error) {
 	opts := &Options{}
 
 	flag.StringVar(&opts.Target, "u", "", "Target URL")
+	flag.StringVar(&opts.TargetList, "l", "", "File containing target URLs, one per line (- for stdin)")
 	flag.IntVar(&opts.Depth, "dep", 1, "Maximum path depth")
 	flag.IntVar(&opts.Timeout, "t", 10, "Request timeout (second)")
 	flag.IntVar(&opts.TotalTimeout, "tt", 0, "Total timeout (second)")
 	flag.Var(&opts.Headers, "H", "HTTP request headers (eg. -H 'Header1:value' -H 'Header2:value')")
 	flag.StringVar(&opts.WordlistPath, "w", "", "Wordlist file path")
+	flag.BoolVar(&opts.WellKnown, "wk", false, "Request well-known and metadata files (security.txt, manifest.json, crossdomain.xml...) of each host")
+	flag.StringVar(&opts.ScopePath, "scope", "", "Scope file with include/exclude rules (YAML or JSON)")
+	flag.StringVar(&opts.NeverVisit, "nv", "", "Paths never to visit, eg. /logout (separated by commas)")
+	flag.StringVar(&opts.MethodMode, "mode", "passive", "Request method policy (passive, active, dry-run)")
+	flag.StringVar(&opts.Methods, "methods", "", "Allowed methods in active mode (separated by commas, default GET,HEAD,OPTIONS,POST,PUT,PATCH)")
+	flag.StringVar(&opts.AuthPath, "auth", "", "Auth file with login flow and logged-out marker (YAML or JSON)")
+	flag.BoolVar(&opts.Polite, "polite", false, "Honor Disallow rules and Crawl-delay in robots.txt")
 	flag.IntVar(&opts.Parallel, "limit", 100, "Maximum number of concurrent requests")
 	flag.BoolVar(&opts.Debug, "debug", false, "Debug mode")
 	flag.BoolVar(&opts.RandomUA, "ua", false, "Use random User-Agent")
@@ -64,11 +116,35 @@ func ParseOptions() (*Options, error) {
 	flag.BoolVar(&opts.VisitSubdomains, "sub", false, "Allow to visit sub-domains")
 	flag.BoolVar(&opts.NoRedirect, "nr", false, "Disallow auto redirect")
 	flag.BoolVar(&opts.UseChrome, "ch", false, "Run Javascript in headless Chrome")
+	flag.IntVar(&opts.Pages, "pages", 5, "Maximum number of browser pages open at the same time in Chrome mode")
+	flag.BoolVar(&opts.Interact, "spa", false, "Click elements and fill forms in headless Chrome to find client-side routes (implies -ch)")
+	flag.IntVar(&opts.InteractDepth, "sd", 2, "Maximum number of consecutive actions in SPA mode")
+	flag.IntVar(&opts.InteractActions, "sa", 30, "Maximum number of actions per page in SPA mode")
+	flag.StringVar(&opts.DisableSources, "nhs", "", "Disable HTML link sources (separated by commas: "+strings.Join(finder.HTMLSourceNames(), ",")+")")
+	flag.BoolVar(&opts.SubmitForms, "submit", false, "Submit forms with generated values (POST forms are subject to the method policy)")
 	flag.BoolVar(&opts.IgnoreQuery, "igq", false, "Ignore the query portion on the URL from a[href]")
 	flag.BoolVar(&opts.JSONFormat, "json", false, "Log as JSON format")
 	flag.StringVar(&opts.StatusFilter, "sf", "", "Filter by status codes (separated by commas)")
 	flag.StringVar(&opts.ExtensionFilter, "ef", "", "Filter by extensions (separated by commas)")
 	flag.StringVar(&opts.LengthFilter, "lf", "", "Filter by response length (separated by commas)")
+	flag.StringVar(&opts.OutputPath, "o", "", "Write results to file")
+	flag.StringVar(&opts.OutputFormat, "of", "text", "Output file format (text, jsonl, csv)")
+	flag.StringVar(&opts.DedupMode, "dedup", "exact", "Deduplicate responses by body (exact, near, none)")
+	flag.IntVar(&opts.DedupThreshold, "dt", 3, "Maximum simhash distance for near-duplicate responses")
+	flag.BoolVar(&opts.StripDynamic, "strip", false, "Strip dynamic tokens (timestamps, CSRF tokens, reflected URLs) before deduplication")
+	flag.IntVar(&opts.WildcardProbes, "wp", 3, "Number of random paths probed to detect wildcard responses in wordlist mode (0 to disable)")
+	flag.StringVar(&opts.StatePath, "state", "", "State file for checkpointing the crawl")
+	flag.IntVar(&opts.CheckpointInterval, "ci", 30, "Checkpoint interval (second)")
+	flag.BoolVar(&opts.Resume, "resume", false, "Resume the crawl from the state file")
+	flag.IntVar(&opts.SitemapLimit, "sml", 1000, "Maximum number of URLs taken from each sitemap or feed (0 for unlimited)")
+	flag.StringVar(&opts.SourceMapDir, "smd", "", "Dump sources recovered from source maps to directory")
+	flag.BoolVar(&opts.SecretScan, "secret", false, "Scan responses for secrets and sensitive data")
+	flag.StringVar(&opts.SecretRules, "sr", "", "Secret rule file merged with the built-in rules (YAML or JSON, implies -secret)")
+	flag.BoolVar(&opts.APIDoc, "apidoc", false, "Probe common Swagger/OpenAPI document paths of each host")
+	flag.BoolVar(&opts.GraphQL, "gql", false, "Probe GraphQL endpoints (common paths, URLs in JS, GraphQL-like responses) by introspection and replay queries")
+	flag.StringVar(&opts.GraphPath, "graph", "", "Export the discovery graph to file (.dot for DOT, otherwise JSON)")
+	flag.StringVar(&opts.ParamsPath, "params", "", "Export parameters of each endpoint to file (JSON Lines)")
+	flag.StringVar(&opts.ParamWordlist, "pw", "", "Export all parameter names to a wordlist file")
 
 	flag.Parse()
 
@@ -79,17 +155,98 @@ func ParseOptions() (*Options, error) {
 	}
 }
 
+// targetURLs 返回所有目标的 URL
+func (opts *Options) targetURLs() []string {
+	urls := make([]string, 0, len(opts.targets))
+	for _, t := range opts.targets {
+		urls = append(urls, t.URL)
+	}
+	return urls
+}
+
 // validateOptions 检查命令选项是否正确
 func validateOptions(opts *Options) error {
-	if opts.Target == "" {
+	var rawTargets []string
+	if opts.Target != "" {
+		rawTargets = append(rawTargets, opts.Target)
+	}
+	if opts.TargetList == "" && opts.Target == "" && stdinPiped() {
+		opts.TargetList = "-"
+	}
+	if opts.TargetList != "" {
+		list, err := readTargets(opts.TargetList)
+		if err != nil {
+			return err
+		}
+		rawTargets = append(rawTargets, list...)
+	}
+	if len(rawTargets) == 0 {
 		return errors.New("target URL is required")
 	}
-	if !util.IsAbsoluteURL(opts.Target) {
-		return errors.New("invalid target URL")
+	for _, raw := range util.Dedup(rawTargets) {
+		t, err := newTarget(raw, opts.VisitSubdomains)
+		if err != nil {
+			return err
+		}
+		opts.targets = append(opts.targets, t)
+	}
+
+	if opts.ScopePath != "" {
+		s, err := scope.Load(opts.ScopePath)
+		if err != nil {
+			return err
+		}
+		opts.scope = s
+	}
+	if opts.NeverVisit != "" {
+		if opts.scope == nil {
+			opts.scope, _ = scope.New(&scope.Config{})
+		}
+		opts.scope.AddNeverVisit(strings.Split(opts.NeverVisit, ",")...)
+	}
+
+	if opts.DisableSources != "" {
+		for _, name := range strings.Split(opts.DisableSources, ",") {
+			if name = strings.TrimSpace(name); !slices.Contains(finder.HTMLSourceNames(), name) {
+				return fmt.Errorf("unknown HTML link source: %s", name)
+			}
+			opts.disabled = append(opts.disabled, name)
+		}
+	}
+
+	p, err := policy.New(opts.MethodMode, opts.Methods)
+	if err != nil {
+		return err
+	}
+	opts.policy = p
+
+	if opts.AuthPath != "" {
+		config, err := auth.LoadConfig(opts.AuthPath)
+		if err != nil {
+			return err
+		}
+		opts.auth = config
+	}
+
+	if opts.Interact {
+		opts.UseChrome = true
+	}
+
+	if opts.SecretScan || opts.SecretRules != "" {
+		var rules []*secret.Rule
+		if opts.SecretRules != "" {
+			if rules, err = secret.Load(opts.SecretRules); err != nil {
+				return err
+			}
+		}
+		if opts.secrets, err = secret.NewScanner(rules); err != nil {
+			return err
+		}
 	}
-	u, _ := url.Parse(opts.Target)
-	opts.targetRoot = fmt.Sprintf("%s://%s/", u.Scheme, u.Host)
 
+	if opts.Resume && opts.StatePath == "" {
+		return errors.New("-resume requires a state file (-state)")
+	}
 	if opts.Proxy != "" && !util.IsAbsoluteURL(opts.Proxy) {
 		return errors.New("invalid proxy URL")
 	}
@@ -102,6 +259,26 @@ func validateOptions(opts *Options) error {
 	}
 	output.SetFormatter(opts.JSONFormat)
 
+	opts.writer = output.NewMultiWriter(output.NewLogWriter())
+	if opts.OutputPath != "" {
+		w, err := output.NewFileWriter(opts.OutputPath, opts.OutputFormat)
+		if err != nil {
+			return err
+		}
+		opts.writer.Add(w)
+	}
+	if opts.GraphPath != "" {
+		format := "graph"
+		if strings.HasSuffix(opts.GraphPath, ".dot") {
+			format = "dot"
+		}
+		w, err := output.NewFileWriter(opts.GraphPath, format)
+		if err != nil {
+			return err
+		}
+		opts.writer.Add(w)
+	}
+
 	if sf := opts.StatusFilter; sf != "" {
 		f, err := filter.NewFilterByName("status", sf)
 		if err != nil {
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"io"
+	"mime"
+	"os"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// recordParams 记录请求 URL 和请求体中的参数
+func (runner *Runner) recordParams(method, link string, body []byte) {
+	if runner.params == nil {
+		return
+	}
+	runner.params.AddURL(method, link)
+	runner.params.AddBody(method, link, body)
+}
+
+// recordResponse 记录 JSON 响应中的字段名
+func (runner *Runner) recordResponse(r *colly.Response) {
+	if runner.params == nil || r.Headers == nil {
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Headers.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		runner.params.AddResponse(r.Request.Method, r.Request.URL.String(), r.Body)
+	}
+}
+
+// exportParams 导出每个接口的参数和所有参数名组成的字典
+func (runner *Runner) exportParams() {
+	opts := runner.options
+	if runner.params == nil {
+		return
+	}
+	if opts.ParamsPath != "" {
+		if err := writeFile(opts.ParamsPath, runner.params.WriteEndpoints); err != nil {
+			log.Errorf("Export parameters error: %s", err)
+		}
+	}
+	if opts.ParamWordlist != "" {
+		if err := writeFile(opts.ParamWordlist, runner.params.WriteWordlist); err != nil {
+			log.Errorf("Export parameter wordlist error: %s", err)
+		}
+	}
+	log.WithField("endpoints", len(runner.params.Endpoints())).Info("Exported parameters.")
+}
+
+func writeFile(name string, write func(w io.Writer) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// 礼貌模式下匹配 robots.txt 规则组使用的名称
+const robotsAgent = "gatherer"
+
+// hostRobots 是一个主机适用于 gatherer 的 robots.txt 规则，以及按照 Crawl-delay 限速的状态
+type hostRobots struct {
+	once  sync.Once
+	group *finder.RobotsGroup
+
+	mutex sync.Mutex
+	next  time.Time // 下一个请求最早的发送时间
+}
+
+// findRobotsLinks 访问 robots.txt 规则中的路径前缀和声明的 sitemap
+func (runner *Runner) findRobotsLinks(r *colly.Response) {
+	robots := finder.ParseRobots(string(r.Body))
+	for _, path := range robots.Paths() {
+		runner.visitLink(r.Request.AbsoluteURL(path), r.Request, output.Provenance{Source: output.SourceRobots, Finder: output.FinderRobots, Raw: path})
+	}
+	for _, sitemap := range robots.Sitemaps {
+		runner.visitLink(r.Request.AbsoluteURL(sitemap), r.Request, output.Provenance{Source: output.SourceRobots, Finder: output.FinderRobotsMap, Raw: sitemap})
+	}
+}
+
+// hostRobots 返回主机的 robots.txt 规则，第一次访问主机时下载，下载失败时不限制
+func (runner *Runner) hostRobots(u *url.URL) *hostRobots {
+	runner.mutex.Lock()
+	hr, ok := runner.robots[u.Host]
+	if !ok {
+		hr = &hostRobots{}
+		runner.robots[u.Host] = hr
+	}
+	runner.mutex.Unlock()
+
+	hr.once.Do(func() {
+		link := u.Scheme + "://" + u.Host + "/robots.txt"
+		sample, err := runner.probe(link)
+		if err != nil || sample.Status != 200 {
+			log.Debug("No robots.txt found: ", link)
+			return
+		}
+		hr.group = finder.ParseRobots(string(sample.Body)).Group(robotsAgent)
+		if hr.group != nil {
+			log.WithFields(log.Fields{"rules": len(hr.group.Rules), "crawl_delay": hr.group.CrawlDelay}).Info("Honor robots.txt: ", link)
+		}
+	})
+	return hr
+}
+
+// wait 按照 Crawl-delay 等待到可以发送下一个请求
+func (hr *hostRobots) wait() {
+	if hr.group == nil || hr.group.CrawlDelay == 0 {
+		return
+	}
+	delay := time.Duration(hr.group.CrawlDelay * float64(time.Second))
+	hr.mutex.Lock()
+	next := hr.next
+	if now := time.Now(); next.Before(now) {
+		next = now
+	}
+	hr.next = next.Add(delay)
+	hr.mutex.Unlock()
+	time.Sleep(time.Until(next))
+}
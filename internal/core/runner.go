@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -22,19 +29,75 @@ import (
 	"github.com/gocolly/colly/v2/debug"
 	"github.com/gocolly/colly/v2/extensions"
 	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/auth"
+	"github.com/zrquan/gatherer/pkg/browser"
+	"github.com/zrquan/gatherer/pkg/dedup"
+	"github.com/zrquan/gatherer/pkg/filter"
 	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+	"github.com/zrquan/gatherer/pkg/param"
 	"github.com/zrquan/gatherer/pkg/util"
 )
 
+// 各类 HTTP 客户端调用对应的 finder
+var clientFinders = map[string]string{
+	finder.ClientFetch:  output.FinderFetch,
+	finder.ClientAxios:  output.FinderAxios,
+	finder.ClientJQuery: output.FinderJQuery,
+	finder.ClientXHR:    output.FinderXHR,
+}
+
+// 各类 chunk 对应的 finder
+var chunkFinders = map[string]string{
+	finder.ChunkWebpack:   output.FinderWebpack,
+	finder.ChunkVite:      output.FinderVite,
+	finder.ChunkImport:    output.FinderImport,
+	finder.ChunkImportMap: output.FinderImportMap,
+}
+
+// 各类 HTML 链接来源对应的 finder
+var htmlFinders = map[string]string{
+	finder.HTMLLink:   output.FinderLinkHref,
+	finder.HTMLIframe: output.FinderIframeSrc,
+	finder.HTMLArea:   output.FinderAreaHref,
+	finder.HTMLBase:   output.FinderBaseHref,
+	finder.HTMLMeta:   output.FinderMeta,
+	finder.HTMLSrcset: output.FinderSrcset,
+	finder.HTMLScript: output.FinderInline,
+	finder.HTMLEvent:  output.FinderEvent,
+	finder.HTMLData:   output.FinderDataAttr,
+}
+
 type Runner struct {
 	mutex        sync.Mutex
 	options      *Options
 	collector    *colly.Collector
 	errorCounter int64
 
-	urlSet  mapset.Set[string]
-	lenSet  mapset.Set[int]
-	browser *rod.Browser
+	urlSet   mapset.Set[string]
+	deduper  *dedup.Deduper
+	browser  *rod.Browser
+	pages    *browser.Pool
+	explorer *browser.Explorer
+	session  *auth.Session
+	params   *param.Inventory
+	forms    mapset.Set[string] // 已经输出的表单
+	robots   map[string]*hostRobots
+	assets   mapset.Set[string] // 已经输出的作用域外主机
+	hosts    mapset.Set[string] // 已经请求过元数据文件的主机
+
+	gqlProbed mapset.Set[string] // 已经发送过内省查询的地址
+	graphql   graphqlState
+
+	// 字典模式下检测通配响应（soft-404）
+	client   *http.Client
+	wildcard *filter.WildcardFilter
+
+	// 断点续爬
+	stateMutex sync.Mutex
+	pending    map[*colly.Context]*pendingRequest
+	cursor     int
+	restored   *checkpoint
 }
 
 func NewRunner(opts *Options) (*Runner, error) {
@@ -43,6 +106,22 @@ func NewRunner(opts *Options) (*Runner, error) {
 		return nil, err
 	}
 
+	deduper, err := dedup.New(opts.DedupMode, opts.DedupThreshold, opts.StripDynamic)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *checkpoint
+	if opts.Resume {
+		restored, err = loadCheckpoint(opts.StatePath)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(restored.Targets, opts.targetURLs()) {
+			return nil, errors.New("state file was created for different targets")
+		}
+	}
+
 	l := launcher.New().
 		Headless(true).
 		Set("ignore-certificate-errors", "1").
@@ -54,58 +133,175 @@ func NewRunner(opts *Options) (*Runner, error) {
 		options:      opts,
 		collector:    collector,
 		errorCounter: 0,
-		urlSet:       mapset.NewSet[string](opts.Target),
-		lenSet:       mapset.NewSet[int](0),
+		urlSet:       mapset.NewSet[string](opts.targetURLs()...),
+		forms:        mapset.NewSet[string](),
+		assets:       mapset.NewSet[string](),
+		hosts:        mapset.NewSet[string](),
+		gqlProbed:    mapset.NewSet[string](),
+		robots:       make(map[string]*hostRobots),
+		deduper:      deduper,
 		browser:      rod.New().ControlURL(l).MustConnect(),
+		pending:      make(map[*colly.Context]*pendingRequest),
+		cursor:       -1,
+		restored:     restored,
+	}
+	if opts.UseChrome {
+		timeout := time.Duration(opts.Timeout) * time.Second
+		runner.pages = browser.NewPool(runner.browser, opts.Pages, timeout, opts.policy.Allow, runner.browserInScope)
+		if opts.Interact {
+			runner.explorer = browser.NewExplorer(runner.pages, opts.InteractDepth, opts.InteractActions)
+		}
+	}
+	if opts.auth != nil {
+		if err := runner.initSession(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ParamsPath != "" || opts.ParamWordlist != "" {
+		runner.params = param.New()
+	}
+	if opts.wordlist != nil && opts.WildcardProbes > 0 {
+		runner.client = newProbeClient(opts)
+		if runner.session != nil {
+			runner.client.Jar = runner.session.Jar()
+		}
+		runner.wildcard = filter.NewWildcardFilter()
+		opts.filters = append(opts.filters, runner.wildcard)
+	}
+	if opts.Polite && runner.client == nil {
+		runner.client = newProbeClient(opts)
+		if runner.session != nil {
+			runner.client.Jar = runner.session.Jar()
+		}
 	}
 	runner.prepareHooks()
 	return runner, nil
 }
 
 func (runner *Runner) Execute() {
-	if tt := runner.options.TotalTimeout; tt <= 0 {
+	defer runner.options.writer.Close()
+
+	finished := make(chan int, 1)
+	go func() {
 		runner.startCollect()
-	} else {
-		finished := make(chan int, 1)
-		go func() {
-			runner.startCollect()
-			finished <- 1
-		}()
-
-		select {
-		case <-finished:
-			close(finished)
+		finished <- 1
+	}()
+
+	var timeout <-chan time.Time
+	if tt := runner.options.TotalTimeout; tt > 0 {
+		timeout = time.After(time.Duration(tt) * time.Second)
+	}
+	// 设置了状态文件时，中断前保存断点
+	interrupt := make(chan os.Signal, 1)
+	if runner.options.StatePath != "" {
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	}
+
+	select {
+	case <-finished:
+		close(finished)
+		if timeout != nil {
 			log.Info("All done.")
-		case <-time.After(time.Duration(tt) * time.Second):
-			log.Error("Gatherer timeout.")
 		}
+	case <-timeout:
+		log.Error("Gatherer timeout.")
+		runner.saveCheckpoint()
+	case <-interrupt:
+		log.Warn("Gatherer interrupted.")
+		runner.saveCheckpoint()
 	}
+	runner.exportParams()
 }
 
 func (runner *Runner) startCollect() {
 	opts := runner.options
-	runner.collector.Visit(opts.Target)
-	if opts.WordlistPath != "" {
-		for opts.wordlist.Next() {
-			path := string(opts.wordlist.Value())
-			link, err := url.JoinPath(opts.Target, path)
-			if err != nil {
-				log.Warn("invalid path from wordlist:", path)
+
+	done := make(chan struct{})
+	go runner.autoCheckpoint(done)
+
+	if runner.restored != nil {
+		runner.resume(runner.restored)
+	} else {
+		for _, t := range opts.targets {
+			ctx := newContext(nil, output.Provenance{Source: output.SourceTarget, Finder: output.FinderTarget})
+			ctx.Put("target", t.URL)
+			runner.recordParams("GET", t.URL, nil)
+			runner.track(ctx, "GET", t.URL, 1, nil, nil)
+			if err := runner.collector.Request("GET", t.URL, nil, ctx, nil); err != nil {
+				runner.untrack(ctx)
+			}
+		}
+	}
+	if opts.WordlistPath != "" && opts.wordlist.Total() > 0 {
+		// 字典条目按 目标序号*字典长度+条目序号 编号，cursor 为最后一个已经发出的编号
+		total := opts.wordlist.Total()
+		for i, t := range opts.targets {
+			if (i+1)*total <= runner.cursor+1 {
 				continue
 			}
-			runner.collector.Visit(link)
+			opts.wordlist.Seek(max(runner.cursor-i*total, -1))
+
+			for opts.wordlist.Next() {
+				path := string(opts.wordlist.Value())
+				link, err := url.JoinPath(t.URL, path)
+				if err != nil {
+					log.Warn("invalid path from wordlist:", path)
+					continue
+				}
+				if !runner.inScope(t, link) {
+					continue
+				}
+				ctx := newContext(nil, output.Provenance{Source: output.SourceWordlist, Finder: output.FinderWordlist, Raw: path})
+				ctx.Put("target", t.URL)
+				if runner.wildcard != nil {
+					runner.learnWildcard(link, t.URL)
+					ctx.Put(filter.WildcardContextKey, link)
+				}
+				runner.track(ctx, "GET", link, 1, nil, nil)
+				if err := runner.collector.Request("GET", link, nil, ctx, nil); err != nil {
+					runner.untrack(ctx)
+				}
+
+				runner.stateMutex.Lock()
+				runner.cursor = i*total + opts.wordlist.Position()
+				runner.stateMutex.Unlock()
+			}
 		}
 	}
 	runner.collector.Wait()
+	close(done)
+	if runner.session != nil {
+		if err := runner.session.Save(); err != nil {
+			log.Warn("Save cookie jar error: ", err)
+		}
+	}
+	if opts.StatePath != "" {
+		// 正常结束后不再需要断点
+		os.Remove(opts.StatePath)
+	}
+	if runner.pages != nil {
+		runner.pages.Close()
+	}
 	runner.browser.MustClose()
+	for _, cluster := range runner.deduper.Clusters() {
+		log.
+			WithFields(log.Fields{"collapsed": cluster.Count, "fingerprint": fmt.Sprintf("%016x", cluster.Fingerprint)}).
+			Info("Duplicate responses of ", cluster.URL)
+	}
+	if len(opts.targets) > 1 {
+		for _, t := range opts.targets {
+			log.
+				WithFields(log.Fields{"visited": atomic.LoadInt64(&t.visited), "error": atomic.LoadInt64(&t.errors)}).
+				Info("Target finished: ", t.URL)
+		}
+	}
 	log.
 		WithFields(log.Fields{"visited": runner.urlSet.Cardinality(), "error": runner.errorCounter}).
 		Info("Gathering finished.")
 }
 
-// 根据命令选项初始化 colly.Collector
-func initCollector(opts *Options) (*colly.Collector, error) {
-	// init Transport
+// newTransport 根据命令选项创建 HTTP Transport
+func newTransport(opts *Options) *http.Transport {
 	tp := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   time.Duration(opts.Timeout) * time.Second,
@@ -120,16 +316,22 @@ func initCollector(opts *Options) (*colly.Collector, error) {
 		proxyURL, _ := url.Parse(opts.Proxy)
 		tp.Proxy = http.ProxyURL(proxyURL)
 	}
+	return tp
+}
 
-	hostname, err := util.ExtractHostname(opts.Target)
-	if err != nil {
-		return nil, err
+// 根据命令选项初始化 colly.Collector
+func initCollector(opts *Options) (*colly.Collector, error) {
+	tp := newTransport(opts)
+
+	var hostnames []string
+	for _, t := range opts.targets {
+		hostnames = append(hostnames, t.hostname)
 	}
 	c := colly.NewCollector(
 		// TODO: 在不同 collector 传递链接时继承请求深度
 		colly.MaxDepth(opts.Depth),
 		colly.Async(true),
-		colly.AllowedDomains(hostname),
+		colly.AllowedDomains(hostnames...),
 	)
 
 	if opts.Debug {
@@ -152,13 +354,15 @@ func initCollector(opts *Options) (*colly.Collector, error) {
 	excludeExtensions := `(?i)\.(png|apng|bmp|gif|ico|cur|jpg|jpeg|jfif|pjp|pjpeg|svg|tif|tiff|webp|xbm|3gp|aac|flac|mpg|mpeg|mp3|mp4|m4a|m4v|m4p|oga|ogg|ogv|mov|wav|webm|eot|woff|woff2|ttf|otf)(?:\?|#|$)`
 	c.DisallowedURLFilters = append(c.DisallowedURLFilters, regexp.MustCompile(excludeExtensions))
 
-	if opts.VisitSubdomains {
-		filter, err := util.FilterSubdomains(hostname)
-		if err != nil {
-			return nil, err
-		}
+	if opts.scope != nil && opts.scope.HasIncludes() {
+		// 由作用域配置文件决定可以访问的主机
 		c.AllowedDomains = nil
-		c.URLFilters = []*regexp.Regexp{filter}
+	} else if opts.VisitSubdomains {
+		c.AllowedDomains = nil
+		c.URLFilters = nil
+		for _, t := range opts.targets {
+			c.URLFilters = append(c.URLFilters, t.subdomains)
+		}
 	}
 
 	c.WithTransport(tp)
@@ -183,17 +387,80 @@ func (runner *Runner) prepareHooks() {
 		})
 	} else {
 		c.SetRedirectHandler(func(req *http.Request, via []*http.Request) error {
+			// 重定向到登录页面时停止跳转，由 reauthenticate 处理
+			if runner.session != nil && runner.session.LoggedOut(0, req.URL.String(), nil) {
+				return http.ErrUseLastResponse
+			}
 			runner.mutex.Lock()
 			defer runner.mutex.Unlock()
 			// 避免多次重定向到同一位置
 			if runner.urlSet.Contains(req.URL.String()) {
 				return http.ErrUseLastResponse
 			}
+			if opts.scope != nil && !opts.scope.Allowed(req.URL) {
+				log.Debug("Skip out-of-scope redirection: ", req.URL.String())
+				return http.ErrUseLastResponse
+			}
 			runner.urlSet.Add(req.URL.String())
 			return nil
 		})
 	}
 
+	// 所有请求（页面链接、字典、Swagger 重放、断点恢复）发出前都检查作用域
+	if opts.scope != nil {
+		c.OnRequest(func(r *colly.Request) {
+			if !opts.scope.Allowed(r.URL) {
+				log.Debug("Skip out-of-scope request: ", r.URL.String())
+				runner.untrack(r.Ctx)
+				r.Abort()
+			}
+		})
+	}
+
+	// 不符合请求方法策略的请求只记录不发送，超出作用域的请求已经被拦截，不再记录
+	c.OnRequest(func(r *colly.Request) {
+		if r.Method == "" || opts.policy == nil || opts.policy.Allow(r.Method) {
+			return
+		}
+		if opts.scope != nil && !opts.scope.Allowed(r.URL) {
+			return
+		}
+		result := newRequestResult(r)
+		result.Skipped = opts.policy.Reason(r.Method)
+		opts.writer.Write(result)
+		runner.untrack(r.Ctx)
+		r.Abort()
+	})
+
+	// 礼貌模式下遵守 robots.txt 的 Disallow 规则和 Crawl-delay，已经被作用域或请求方法策略拦截的请求不再检查
+	if opts.Polite {
+		c.OnRequest(func(r *colly.Request) {
+			if r.URL.Path == "/robots.txt" ||
+				opts.scope != nil && !opts.scope.Allowed(r.URL) ||
+				opts.policy != nil && !opts.policy.Allow(r.Method) {
+				return
+			}
+			hr := runner.hostRobots(r.URL)
+			if !hr.group.Allowed(r.URL.RequestURI()) {
+				result := newRequestResult(r)
+				result.Skipped = "disallowed by robots.txt"
+				opts.writer.Write(result)
+				runner.untrack(r.Ctx)
+				r.Abort()
+				return
+			}
+			hr.wait()
+		})
+	}
+
+	// 记录发出请求时的登录序号和请求地址，会话失效时据此判断是否需要重新登录
+	if runner.session != nil {
+		c.OnRequest(func(r *colly.Request) {
+			r.Ctx.Put("session", strconv.FormatInt(runner.session.Generation(), 10))
+			r.Ctx.Put("requested", r.URL.String())
+		})
+	}
+
 	// 设置请求头
 	for _, h := range opts.Headers {
 		headerArgs := strings.SplitN(h, ":", 2)
@@ -205,16 +472,43 @@ func (runner *Runner) prepareHooks() {
 	}
 
 	c.OnError(func(r *colly.Response, err error) {
+		runner.untrack(r.Ctx)
+		if runner.reauthenticate(r) {
+			return
+		}
+		t := runner.targetOf(r.Ctx)
+
 		status := r.StatusCode
 		link := r.Request.URL.String()
 
+		if status > 0 {
+			runner.discoverHost(r.Request)
+			if r.Ctx.Get("finder") == output.FinderIntrospect {
+				runner.handleIntrospection(r)
+			} else {
+				runner.detectGraphQL(r)
+			}
+		}
+
+		// 不存在的 source map、元数据文件以及猜测的 GraphQL 接口和 API 文档不输出
+		if missingFile(r) || status == 404 && (r.Ctx.Get("finder") == output.FinderMapProbe ||
+			r.Ctx.Get("finder") == output.FinderIntrospect && r.Ctx.Get("source") == string(output.SourceGraphQL)) {
+			return
+		}
+
+		// 服务端错误页面中可能有调用栈、内部地址等信息
+		if status >= 500 {
+			runner.scanResponse(r)
+		}
+
 		if status >= 300 && status < 400 {
 			location := r.Headers.Get("Location")
 			if location == link+"/" {
-				runner.visitLink(location, r.Request)
+				runner.visitLink(location, r.Request, output.Provenance{Source: output.SourceRedirect, Finder: output.FinderLocation, Raw: location})
 				return
 			}
 		}
+		runner.findHeaderLinks(r)
 
 		for _, f := range opts.filters {
 			result, err := f.Filter(r)
@@ -226,34 +520,65 @@ func (runner *Runner) prepareHooks() {
 			}
 		}
 
-		log.WithFields(log.Fields{
-			"code":   status,
-			"length": len(r.Body),
-		}).Warn(r.Request.URL.String())
+		result := newResult(r)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		opts.writer.Write(result)
 
 		atomic.AddInt64(&runner.errorCounter, 1)
+		if t != nil {
+			atomic.AddInt64(&t.errors, 1)
+		}
 	})
 
 	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
-		link := e.Request.AbsoluteURL(e.Attr("href"))
+		href := e.Attr("href")
+		link := e.Request.AbsoluteURL(href)
 		if opts.IgnoreQuery {
+			// 去掉查询字符串前记录其中的参数
+			if runner.inScope(runner.targetOf(e.Request.Ctx), link) {
+				runner.recordParams("GET", link, nil)
+			}
 			u, err := url.Parse(link)
 			if err != nil {
 				log.WithField("link", link).Error("Parse URL error")
 			}
 			link = util.StripQueryParams(u)
 		}
-		runner.visitLink(link, e.Request)
+		runner.visitLink(link, e.Request, output.Provenance{Source: output.SourceHTML, Finder: output.FinderAHref, Raw: href})
 	})
 
 	c.OnHTML("script[src]", func(e *colly.HTMLElement) {
-		link := e.Request.AbsoluteURL(e.Attr("src"))
-		runner.visitLink(link, e.Request)
+		src := e.Attr("src")
+		link := e.Request.AbsoluteURL(src)
+		runner.visitLink(link, e.Request, output.Provenance{Source: output.SourceHTML, Finder: output.FinderScriptSrc, Raw: src})
+	})
+
+	c.OnHTML("script[type=importmap]", func(e *colly.HTMLElement) {
+		for _, link := range finder.FindLinksFromImportMap(e.Text) {
+			runner.visitLink(e.Request.AbsoluteURL(link), e.Request, output.Provenance{Source: output.SourceHTML, Finder: output.FinderImportMap, Raw: link})
+		}
 	})
 
+	// colly 会根据 <base href> 解析相对链接
+	for _, src := range finder.HTMLSources {
+		if slices.Contains(opts.disabled, src.Name) {
+			continue
+		}
+		c.OnHTML(src.Selector, func(e *colly.HTMLElement) {
+			for _, raw := range src.Extract(e.DOM) {
+				runner.visitLink(e.Request.AbsoluteURL(raw), e.Request, output.Provenance{Source: output.SourceHTML, Finder: htmlFinders[src.Name], Raw: raw})
+			}
+		})
+	}
+
+	c.OnHTML("form", runner.handleForm)
+
 	c.OnHTML("form[action]", func(e *colly.HTMLElement) {
-		link := e.Request.AbsoluteURL(e.Attr("action"))
-		runner.visitLink(link, e.Request)
+		action := e.Attr("action")
+		link := e.Request.AbsoluteURL(action)
+		runner.visitLink(link, e.Request, output.Provenance{Source: output.SourceHTML, Finder: output.FinderFormAction, Raw: action})
 	})
 
 	c.OnHTML("title", func(e *colly.HTMLElement) {
@@ -263,42 +588,46 @@ func (runner *Runner) prepareHooks() {
 		}
 
 		if title == "Swagger UI" && strings.HasSuffix(e.Request.URL.Path, "swagger-ui.html") {
-			runner.visitLink(e.Request.AbsoluteURL("swagger-resources"), e.Request)
+			prov := output.Provenance{Source: output.SourceHTML, Finder: output.FinderTitle, Raw: title}
+			runner.visitLink(e.Request.AbsoluteURL("swagger-resources"), e.Request, prov)
 		}
 	})
 
-	// sitemap
-	c.OnXML("//urlset/url/loc", func(e *colly.XMLElement) {
-		link := e.Text
-		runner.visitLink(link, e.Request)
-	})
-
 	c.OnResponse(func(r *colly.Response) {
+		if runner.reauthenticate(r) {
+			// 不再从登录页面中收集链接
+			r.Body = nil
+			return
+		}
+		runner.discoverHost(r.Request)
+		// 猜测的文件不存在时不再从通配路由返回的页面中收集信息
+		if missingFile(r) {
+			log.Debug("Guessed file does not exist: ", r.Request.URL.String())
+			return
+		}
+		runner.findHeaderLinks(r)
+		if r.Ctx.Get("finder") == output.FinderIntrospect {
+			runner.handleIntrospection(r)
+		} else {
+			runner.detectGraphQL(r)
+		}
 		if runner.filterResp(r) {
 			return
 		}
+		if !util.IsSourceMap(r.Request.URL.String()) {
+			runner.scanResponse(r)
+		}
+		runner.recordResponse(r)
 
-		if opts.UseChrome {
-			page := runner.browser.MustPage()
-
-			err := rod.Try(func() {
-				page.
-					Timeout(time.Duration(opts.Timeout) * time.Second).
-					MustNavigate(r.Request.URL.String()).
-					MustWaitLoad()
-				content, _ := page.HTML()
-				r.Body = []byte(content)
-			})
-			if err != nil {
+		if opts.UseChrome && r.Headers != nil && strings.Contains(r.Headers.Get("Content-Type"), "html") {
+			if err := runner.render(r); err != nil {
 				if errors.Is(err, context.DeadlineExceeded) {
 					log.Warn("browser timeout to visit:", r.Request.URL.String())
 				}
-				page.MustClose()
 				return
 			}
 		}
 
-		// TODO: support openapi 3.x
 		if util.IsSwaggerSchema(r) {
 			endpoints, err := finder.FindLinksFromSwagger(r.Body)
 			if err != nil {
@@ -306,11 +635,14 @@ func (runner *Runner) prepareHooks() {
 			} else {
 				log.Printf("Found %d APIs from Swagger document: %s", len(endpoints), r.Request.URL.String())
 				for _, api := range endpoints {
-					url := util.FixURL(r.Request.URL, api.URL)
-					method := strings.ToUpper(api.Method)
-					if method == "DELETE" {
-						continue
+					// OpenAPI 3.x 的 servers 可能是绝对地址
+					var url string
+					if util.IsAbsoluteURL(api.URL) {
+						url = api.URL
+					} else {
+						url = util.FixURL(r.Request.URL, api.URL)
 					}
+					method := strings.ToUpper(api.Method)
 
 					dataReader := bytes.NewReader([]byte(api.Content))
 					headers := r.Request.Headers.Clone()
@@ -318,53 +650,114 @@ func (runner *Runner) prepareHooks() {
 						headers.Set(k, v)
 					}
 
-					c.Request(method, url, dataReader, r.Ctx, headers)
+					if !runner.inScope(runner.targetOf(r.Ctx), url) {
+						continue
+					}
+
+					runner.recordParams(method, url, []byte(api.Content))
+					prov := output.Provenance{Source: output.SourceSwagger, Finder: output.FinderSwagger, Raw: method + " " + api.URL}
+					ctx := newContext(r.Request, prov)
+					runner.track(ctx, method, url, 1, []byte(api.Content), headers)
+					if err := c.Request(method, url, dataReader, ctx, headers); err != nil {
+						runner.untrack(ctx)
+					}
 				}
 			}
 		}
 
 		if util.IsScriptOrJSON(r.Request.URL.String()) {
-			endpoints := mapset.NewSet[string]()
+			// endpoint -> 提取它的 finder
+			endpoints := make(map[string]string)
 
 			content := string(r.Body)
-			if strings.Contains(content, `document.createElement("script");`) {
-				dynamicLinks := finder.FindDynamicLinksFromJS(content, runner.browser)
-				for _, dl := range dynamicLinks {
-					log.Debugf("Found dynamic script \"%s\" from JS file: %s", dl, r.Request.URL.String())
-					endpoints.Add(dl)
+			chunks := finder.FindChunksFromJS(content)
+			for _, chunk := range chunks {
+				log.Debugf("Found %s chunk \"%s\" from JS file: %s", chunk.Kind, chunk.Path, r.Request.URL.String())
+				link := chunk.Path
+				if chunk.Relative || strings.HasPrefix(link, "/") || util.IsAbsoluteURL(link) {
+					// 相对于当前 JS 文件
+					link = r.Request.AbsoluteURL(link)
+				} else {
+					link = runner.resolveEndpoint(r.Request, link)
 				}
+				runner.visitLink(link, r.Request, output.Provenance{Source: output.SourceJS, Finder: chunkFinders[chunk.Kind], Raw: chunk.Path})
 			}
 
-			endpoints.Append(finder.FindLinksFromJS(content)...)
-			log.Debugf("Found %d links from JS file: %s", endpoints.Cardinality(), r.Request.URL.String())
+			runner.findGraphQLFromJS(r, content)
 
-			for ep := range endpoints.Iterator().C {
-				var link string
-				if strings.HasPrefix(ep, "./") {
-					link = util.FixURL(r.Request.URL, ep)
-				} else {
-					u, _ := url.Parse(opts.targetRoot)
-					link = util.FixURL(u, ep)
+			calls, err := finder.FindEndpointsFromJS(content)
+			if err != nil {
+				log.Debugf("Parse JS error: %s", err)
+			}
+			for _, ep := range calls {
+				runner.visitEndpoint(r.Request, ep)
+			}
+
+			for _, ep := range finder.FindLinksFromJS(content) {
+				if slices.ContainsFunc(chunks, func(c *finder.Chunk) bool { return c.Path == ep }) ||
+					slices.ContainsFunc(calls, func(c *finder.Endpoint) bool {
+						// 拼接 URL 的片段
+						return c.URL == ep || strings.Contains(c.Raw, `"`+ep+`"`) || strings.Contains(c.Raw, "'"+ep+"'")
+					}) {
+					continue
+				}
+				endpoints[ep] = output.FinderLinkRegex
+			}
+			log.Debugf("Found %d links from JS file: %s", len(endpoints)+len(chunks)+len(calls), r.Request.URL.String())
+
+			for ep, f := range endpoints {
+				link := runner.resolveEndpoint(r.Request, ep)
+				prov := output.Provenance{Source: output.SourceJS, Finder: f, Raw: ep}
+				if err == nil {
+					// 语法分析没有发现的字符串可能只是看起来像路径
+					prov.Confidence = finder.ConfidenceLow
 				}
-				runner.visitLink(link, r.Request)
+				runner.visitLink(link, r.Request, prov)
 			}
 		}
 
+		ext := util.GetExtension(r.Request.URL.String())
+		if ext == ".js" || ext == ".mjs" || ext == ".css" {
+			runner.findSourceMap(r)
+		}
+		if util.IsSourceMap(r.Request.URL.String()) {
+			runner.handleSourceMap(r.Request, r.Body)
+		}
+
+		if r.Ctx.Get("finder") == output.FinderWellKnown {
+			runner.findWellKnownLinks(r)
+		}
+
+		runner.findAPIDocLinks(r)
+
+		if isSitemap(r) {
+			runner.findSitemapLinks(r)
+		}
+
 		if r.StatusCode == 200 && r.Request.URL.Path == "/robots.txt" {
-			endpoints := finder.FindLinksFromRobots(string(r.Body))
-			for _, e := range endpoints {
-				link := r.Request.AbsoluteURL(e)
-				runner.visitLink(link, r.Request)
-			}
+			runner.findRobotsLinks(r)
 		}
 	})
 
 	c.OnScraped(func(r *colly.Response) {
+		runner.untrack(r.Ctx)
+		if r.Ctx.Get("logged_out") != "" {
+			return
+		}
+
 		runner.mutex.Lock()
 		defer runner.mutex.Unlock()
 
 		url := r.Request.URL.String()
 		runner.urlSet.Add(url)
+		if t := runner.targetOf(r.Ctx); t != nil {
+			atomic.AddInt64(&t.visited, 1)
+		}
+
+		// 通配路由对猜测的文件返回的页面不是结果
+		if missingFile(r) {
+			return
+		}
 
 		for _, f := range opts.filters {
 			result, err := f.Filter(r)
@@ -376,35 +769,278 @@ func (runner *Runner) prepareHooks() {
 			}
 		}
 
-		var fields log.Fields
-		if t := r.Ctx.Get("title"); t != "" {
-			fields = log.Fields{"code": r.StatusCode, "length": len(r.Body), "title": t}
-			// reset page title
-			r.Ctx.Put("title", "")
+		opts.writer.Write(newResult(r))
+	})
+}
+
+// visitLink 访问从 request 的响应中发现的链接，新请求使用独立的上下文以保存来源信息
+func (runner *Runner) visitLink(link string, request *colly.Request, prov output.Provenance) {
+	runner.visitRequest("GET", link, nil, "", request, prov)
+}
+
+// visitRequest 使用指定的方法和请求体访问发现的链接，contentType 为空时根据请求体判断
+func (runner *Runner) visitRequest(method, link string, body []byte, contentType string, request *colly.Request, prov output.Provenance) {
+	link = request.AbsoluteURL(link)
+	// 只访问同一目标作用域内的链接
+	if !runner.inScope(runner.targetOf(request.Ctx), link) {
+		return
+	}
+	// 已经访问过的链接也可能带有新的参数
+	runner.recordParams(method, link, body)
+	runner.sendRequest(method, link, body, contentType, request, prov)
+}
+
+// sendRequest 发出已经通过作用域检查的请求，GET 请求访问过的链接不再访问
+func (runner *Runner) sendRequest(method, link string, body []byte, contentType string, request *colly.Request, prov output.Provenance) {
+	if method == "GET" && runner.urlSet.Contains(link) {
+		return
+	}
+	var data io.Reader
+	if len(body) > 0 {
+		data = bytes.NewReader(body)
+	}
+	req, err := request.New(method, link, data)
+	if err != nil {
+		return
+	}
+	req.Ctx = newContext(request, prov)
+	req.Depth = request.Depth + 1
+	req.Headers = &http.Header{"User-Agent": []string{runner.collector.UserAgent}}
+	if contentType != "" {
+		req.Headers.Set("Content-Type", contentType)
+	} else if len(body) > 0 && body[0] == '{' && json.Valid(body) {
+		req.Headers.Set("Content-Type", "application/json")
+	}
+	runner.track(req.Ctx, method, req.URL.String(), req.Depth, body, *req.Headers)
+	if err := req.Do(); err != nil {
+		runner.untrack(req.Ctx)
+	}
+}
+
+// visitEndpoint 按照 JS 代码中的客户端调用发出请求，请求体中的参数使用空值
+func (runner *Runner) visitEndpoint(request *colly.Request, ep *finder.Endpoint) {
+	link := ep.URL
+	if strings.HasPrefix(link, "ws://") || strings.HasPrefix(link, "wss://") {
+		return
+	}
+	if !util.IsAbsoluteURL(link) {
+		link = runner.resolveEndpoint(request, link)
+	}
+
+	var body []byte
+	if ep.Method != "GET" && ep.Method != "HEAD" && len(ep.Params) > 0 {
+		if ep.JSON {
+			params := make(map[string]string)
+			for _, p := range ep.Params {
+				params[p] = ""
+			}
+			body, _ = json.Marshal(params)
 		} else {
-			fields = log.Fields{"code": r.StatusCode, "length": len(r.Body)}
+			params := url.Values{}
+			for _, p := range ep.Params {
+				params.Set(p, "")
+			}
+			body = []byte(params.Encode())
+		}
+	}
+
+	f, ok := clientFinders[ep.Client]
+	if !ok {
+		f = output.FinderJSExpr
+	}
+	log.Debugf("Found %s %s from JS call: %s", ep.Method, ep.URL, ep.Raw)
+	link = request.AbsoluteURL(link)
+	if !runner.inScope(runner.targetOf(request.Ctx), link) {
+		return
+	}
+	// 请求体只是用空值填充的参数，只按 JS 调用记录一次
+	if runner.params != nil {
+		runner.params.AddURL(ep.Method, link)
+		runner.params.Add(ep.Method, link, param.LocationJS, ep.Params...)
+	}
+	runner.sendRequest(ep.Method, link, body, "", request, output.Provenance{Source: output.SourceJS, Finder: f, Raw: ep.Raw, Confidence: ep.Confidence})
+}
+
+// render 在浏览器中渲染页面，使用渲染后的 HTML 替换响应体，SPA 模式下还会与页面交互
+func (runner *Runner) render(r *colly.Response) error {
+	doc := &browser.Document{
+		URL:    r.Request.URL.String(),
+		Status: r.StatusCode,
+		Header: *r.Headers,
+		Body:   r.Body,
+	}
+	if runner.explorer == nil {
+		content, requests, err := runner.pages.Render(doc)
+		if err != nil {
+			return err
 		}
+		r.Body = []byte(content)
+		runner.visitBrowserRequests(requests, r.Request)
+		return nil
+	}
 
-		log.WithFields(fields).Info(url)
+	exploration, err := runner.explorer.Explore(doc)
+	if err != nil {
+		return err
+	}
+	r.Body = []byte(exploration.HTML)
+	runner.visitBrowserRequests(exploration.Requests, r.Request)
+	if len(exploration.Routes) > 0 {
+		log.Debugf("Found %d routes by interacting with page: %s", len(exploration.Routes), doc.URL)
+	}
+	for _, route := range exploration.Routes {
+		prov := output.Provenance{Source: output.SourceBrowser, Finder: output.FinderRoute, Raw: route}
+		if link, _, _ := strings.Cut(route, "#"); link != r.Request.URL.String() {
+			runner.visitLink(link, r.Request, prov)
+			continue
+		}
+		// 只有 hash 不同的前端路由无法由 collector 访问，直接输出
+		runner.writeDiscovered(route, "GET", r.Request, prov)
+	}
+	return nil
+}
+
+// writeDiscovered 输出只记录不访问的链接
+func (runner *Runner) writeDiscovered(link, method string, request *colly.Request, prov output.Provenance) {
+	if !runner.inScope(runner.targetOf(request.Ctx), link) {
+		return
+	}
+	runner.options.writer.Write(&output.Result{
+		URL:        link,
+		Method:     method,
+		SourceURL:  request.URL.String(),
+		Source:     prov.Source,
+		Finder:     prov.Finder,
+		Raw:        prov.Raw,
+		Confidence: prov.Confidence,
+		Depth:      request.Depth + 1,
+		Target:     request.Ctx.Get("target"),
 	})
 }
 
-func (runner *Runner) visitLink(link string, request *colly.Request) {
-	if !runner.urlSet.Contains(link) {
-		request.Visit(link)
+// visitBrowserRequests 将浏览器加载页面时发出的请求作为新发现的链接，WebSocket 地址只记录不访问
+func (runner *Runner) visitBrowserRequests(requests []*browser.Request, request *colly.Request) {
+	for _, br := range requests {
+		prov := output.Provenance{Source: output.SourceBrowser, Finder: br.Type, Raw: br.Method + " " + br.URL}
+		if br.Type == output.FinderWebSocket {
+			runner.writeDiscovered(br.URL, br.Method, request, prov)
+			continue
+		}
+		log.Debugf("Found %s request from browser: %s %s", br.Type, br.Method, br.URL)
+		runner.visitRequest(br.Method, br.URL, []byte(br.Body), "", request, prov)
 	}
 }
 
+// resolveEndpoint 将 JS 代码中的路径转换为完整的 URL，./ 开头的路径相对于 JS 文件，其他路径相对于目标的根路径
+func (runner *Runner) resolveEndpoint(request *colly.Request, ep string) string {
+	if strings.HasPrefix(ep, "./") {
+		return util.FixURL(request.URL, ep)
+	}
+	root := request.URL.Scheme + "://" + request.URL.Host + "/"
+	if t := runner.targetOf(request.Ctx); t != nil {
+		root = t.root
+	}
+	u, _ := url.Parse(root)
+	return util.FixURL(u, ep)
+}
+
+// newContext 创建请求上下文，记录链接的发现方式和父页面
+func newContext(parent *colly.Request, prov output.Provenance) *colly.Context {
+	ctx := colly.NewContext()
+	ctx.Put("source", string(prov.Source))
+	ctx.Put("finder", prov.Finder)
+	ctx.Put("raw", prov.Raw)
+	if prov.Confidence != "" {
+		ctx.Put("confidence", prov.Confidence)
+	}
+	if parent != nil {
+		ctx.Put("parent", parent.URL.String())
+		ctx.Put("target", parent.Ctx.Get("target"))
+	}
+	return ctx
+}
+
+// inScope 判断链接是否在作用域内：配置了 include 规则时以规则为准，否则只允许访问目标自身的主机
+func (runner *Runner) inScope(t *target, link string) bool {
+	if s := runner.options.scope; s != nil {
+		u, err := url.Parse(link)
+		if err != nil || s.Excluded(u) {
+			return false
+		}
+		if s.HasIncludes() {
+			return s.Included(u)
+		}
+	}
+	return t == nil || t.inScope(link)
+}
+
+// browserInScope 判断浏览器能否请求链接。页面池由所有目标共用，链接在 scope 文件和任意一个目标的作用域内即可
+func (runner *Runner) browserInScope(link string) bool {
+	if len(runner.options.targets) == 0 {
+		return runner.inScope(nil, link)
+	}
+	return slices.ContainsFunc(runner.options.targets, func(t *target) bool { return runner.inScope(t, link) })
+}
+
+// targetOf 返回请求所属的目标
+func (runner *Runner) targetOf(ctx *colly.Context) *target {
+	link := ctx.Get("target")
+	for _, t := range runner.options.targets {
+		if t.URL == link {
+			return t
+		}
+	}
+	return nil
+}
+
+// newRequestResult 根据未发送的请求生成结果
+func newRequestResult(r *colly.Request) *output.Result {
+	return &output.Result{
+		URL:        r.URL.String(),
+		Method:     r.Method,
+		SourceURL:  r.Ctx.Get("parent"),
+		Source:     output.Source(r.Ctx.Get("source")),
+		Finder:     r.Ctx.Get("finder"),
+		Raw:        r.Ctx.Get("raw"),
+		Confidence: r.Ctx.Get("confidence"),
+		Depth:      r.Depth,
+		Target:     r.Ctx.Get("target"),
+	}
+}
+
+// newResult 根据响应生成结构化的结果
+func newResult(r *colly.Response) *output.Result {
+	result := &output.Result{
+		URL:        r.Request.URL.String(),
+		Method:     r.Request.Method,
+		Status:     r.StatusCode,
+		Length:     len(r.Body),
+		Title:      r.Ctx.Get("title"),
+		SourceURL:  r.Ctx.Get("parent"),
+		Source:     output.Source(r.Ctx.Get("source")),
+		Finder:     r.Ctx.Get("finder"),
+		Raw:        r.Ctx.Get("raw"),
+		Confidence: r.Ctx.Get("confidence"),
+		Depth:      r.Request.Depth,
+		Target:     r.Ctx.Get("target"),
+	}
+	if r.Headers != nil {
+		result.ContentType = r.Headers.Get("Content-Type")
+	}
+	return result
+}
+
 func (runner *Runner) filterResp(resp *colly.Response) bool {
 	if resp.StatusCode == 404 || resp.StatusCode == 429 || resp.StatusCode < 100 {
 		return true
 	}
 
-	// 长度一样的话视为相同的响应报文，只处理一次（应该用哈希值？）
-	if runner.lenSet.Contains(len(resp.Body)) {
-		return true
-	} else {
-		runner.lenSet.Add(len(resp.Body))
-		return false
+	if runner.wildcard != nil {
+		if matched, _ := runner.wildcard.Filter(resp); matched {
+			return true
+		}
 	}
+
+	// 内容相同（或相近）的响应报文只处理一次
+	return runner.deduper.IsDuplicate(resp.Request.URL.String(), resp.Body)
 }
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/zrquan/gatherer/pkg/output"
+	"github.com/zrquan/gatherer/pkg/secret"
+)
+
+// scanResponse 检测响应内容中的敏感信息，二进制内容和 source map 不检测（source map 在还原源码后检测）
+func (runner *Runner) scanResponse(r *colly.Response) {
+	if runner.options.secrets == nil || len(r.Body) == 0 {
+		return
+	}
+	if r.Headers != nil {
+		mediaType, _, _ := mime.ParseMediaType(r.Headers.Get("Content-Type"))
+		if isBinaryMediaType(mediaType) {
+			return
+		}
+	}
+	runner.scanSecrets(r.Request.URL.String(), r.Body, r.Request)
+}
+
+// scanSecrets 检测 content 中的敏感信息，每条敏感信息作为单独的结果输出，link 是内容所在的位置
+func (runner *Runner) scanSecrets(link string, content []byte, request *colly.Request) {
+	for _, f := range runner.options.secrets.Scan(link, content) {
+		runner.options.writer.Write(newSecretResult(f, request))
+	}
+}
+
+func newSecretResult(f *secret.Finding, request *colly.Request) *output.Result {
+	return &output.Result{
+		URL:       f.URL,
+		SourceURL: request.Ctx.Get("parent"),
+		Finder:    f.Rule.ID,
+		Raw:       f.Match,
+		Depth:     request.Depth,
+		Target:    request.Ctx.Get("target"),
+		Type:      output.TypeSecret,
+		Secret: &output.Secret{
+			Rule:        f.Rule.ID,
+			Description: f.Rule.Description,
+			Severity:    f.Rule.Severity,
+			Match:       f.Match,
+			Line:        f.Line,
+			Column:      f.Column,
+			Offset:      f.Offset,
+			Entropy:     f.Entropy,
+		},
+	}
+}
+
+func isBinaryMediaType(mediaType string) bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mediaType, prefix) && mediaType != "image/svg+xml" {
+			return true
+		}
+	}
+	switch mediaType {
+	case "application/octet-stream", "application/pdf", "application/zip", "application/gzip", "application/wasm":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/auth"
+)
+
+// initSession 创建登录会话，collector、探测请求和浏览器共享会话的 cookie jar。
+// 没有可用的 cookie 时先登录一次
+func (runner *Runner) initSession() error {
+	opts := runner.options
+
+	header := http.Header{}
+	header.Set("User-Agent", runner.collector.UserAgent)
+	for _, h := range opts.Headers {
+		if k, v, ok := strings.Cut(h, ":"); ok {
+			header.Set(strings.TrimSpace(k), strings.TrimSpace(v))
+		}
+	}
+
+	session, err := auth.NewSession(opts.auth, newTransport(opts), header, runner.browser)
+	if err != nil {
+		return err
+	}
+	runner.collector.SetCookieJar(session.Jar())
+	runner.session = session
+
+	if session.CanLogin() && session.Jar().Len() == 0 {
+		return session.Login()
+	}
+	return nil
+}
+
+// reauthenticate 检测到会话失效时重新登录，并使用新的会话重新发出请求。
+// 每个请求只重试一次，返回 true 表示请求已经重新发出
+func (runner *Runner) reauthenticate(r *colly.Response) bool {
+	s := runner.session
+	if s == nil {
+		return false
+	}
+	location := redirectLocation(r)
+	if !s.LoggedOut(r.StatusCode, location, r.Body) {
+		return false
+	}
+
+	link := r.Request.URL.String()
+	if !s.CanLogin() || r.Ctx.Get("reauth") != "" {
+		log.Warn("Session expired: ", link)
+		return false
+	}
+	generation, _ := strconv.ParseInt(r.Ctx.Get("session"), 10, 64)
+	if err := s.Refresh(generation); err != nil {
+		log.Error("Re-authentication failed: ", err)
+		return false
+	}
+
+	log.Debug("Retry request after re-authentication: ", link)
+	runner.retry(r.Request)
+	r.Ctx.Put("logged_out", "1")
+	return true
+}
+
+// retry 使用新的上下文重新发出请求，不检查是否已经访问过
+func (runner *Runner) retry(request *colly.Request) {
+	var body []byte
+	if request.Body != nil {
+		if seeker, ok := request.Body.(io.Seeker); ok {
+			seeker.Seek(0, io.SeekStart)
+			body, _ = io.ReadAll(request.Body)
+		}
+	}
+	req, err := request.New(request.Method, request.URL.String(), nil)
+	if err != nil {
+		return
+	}
+	if body != nil {
+		req.Body = bytes.NewReader(body)
+	}
+
+	ctx := colly.NewContext()
+	for k, v := range contextValues(request.Ctx) {
+		ctx.Put(k, v)
+	}
+	ctx.Put("reauth", "1")
+	req.Ctx = ctx
+	req.Depth = request.Depth
+	headers := request.Headers.Clone()
+	req.Headers = &headers
+
+	runner.track(ctx, req.Method, req.URL.String(), req.Depth, body, headers)
+	// Retry 会移除旧的 Cookie 请求头，使用 jar 中新的 cookie
+	if err := req.Retry(); err != nil {
+		runner.untrack(ctx)
+	}
+}
+
+// redirectLocation 返回响应跳转到的地址，没有发生跳转时返回空字符串。
+// 直接访问的页面（如登录页面本身）不能作为会话失效的跳转地址
+func redirectLocation(r *colly.Response) string {
+	if r.Headers != nil {
+		if l := r.Headers.Get("Location"); l != "" {
+			return r.Request.AbsoluteURL(l)
+		}
+	}
+	// 自动跳转后的最终地址与发出请求时的地址不同
+	if requested := r.Ctx.Get("requested"); requested != "" && requested != r.Request.URL.String() {
+		return r.Request.URL.String()
+	}
+	return ""
+}
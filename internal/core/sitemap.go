@@ -0,0 +1,46 @@
+package core
+
+import (
+	"mime"
+	"regexp"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// sitemap、sitemap index 和 RSS/Atom feed 常见的路径
+var sitemapPathRegex = regexp.MustCompile(`(?i)(sitemap[^/]*\.(xml|txt)(\.gz)?|\.xml\.gz|/(feed|rss|atom)(\.xml)?/?|\.(rss|atom))$`)
+
+// isSitemap 根据链接的来源、路径和 Content-Type 判断响应是否为 sitemap 或 feed
+func isSitemap(r *colly.Response) bool {
+	if r.StatusCode != 200 {
+		return false
+	}
+	if f := r.Ctx.Get("finder"); f == output.FinderSitemapIdx || f == output.FinderRobotsMap || sitemapPathRegex.MatchString(r.Request.URL.Path) {
+		return true
+	}
+	if r.Headers != nil {
+		mediaType, _, _ := mime.ParseMediaType(r.Headers.Get("Content-Type"))
+		return mediaType == "application/rss+xml" || mediaType == "application/atom+xml"
+	}
+	return false
+}
+
+// findSitemapLinks 访问 sitemap 或 feed 中的链接，sitemap index 中的子 sitemap 会继续解析
+func (runner *Runner) findSitemapLinks(r *colly.Response) {
+	link := r.Request.URL.String()
+	sm, err := finder.ParseSitemap(r.Body, runner.options.SitemapLimit)
+	if err != nil {
+		log.WithField("error", err).Debug("Parse sitemap error: ", link)
+		return
+	}
+	log.Debugf("Found %d links and %d sitemaps from sitemap: %s", len(sm.URLs), len(sm.Sitemaps), link)
+	for _, s := range sm.Sitemaps {
+		runner.visitLink(s, r.Request, output.Provenance{Source: output.SourceSitemap, Finder: output.FinderSitemapIdx, Raw: s})
+	}
+	for _, u := range sm.URLs {
+		runner.visitLink(u, r.Request, output.Provenance{Source: output.SourceSitemap, Finder: output.FinderSitemap, Raw: u})
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// findSourceMap 访问 JS 或 CSS 文件引用的 source map，没有引用时尝试访问同名的 .map 文件
+func (runner *Runner) findSourceMap(r *colly.Response) {
+	ref := finder.FindSourceMapURL(string(r.Body))
+	if ref == "" && r.Headers != nil {
+		ref = r.Headers.Get("SourceMap")
+		if ref == "" {
+			ref = r.Headers.Get("X-SourceMap")
+		}
+	}
+
+	if finder.IsInlineSourceMap(ref) {
+		data, err := finder.DecodeInlineSourceMap(ref)
+		if err != nil {
+			log.WithField("error", err).Debug("Decode inline source map error: ", r.Request.URL.String())
+			return
+		}
+		runner.handleSourceMap(r.Request, data)
+		return
+	}
+
+	prov := output.Provenance{Source: output.SourceJS, Finder: output.FinderSourceMap, Raw: ref}
+	if ref == "" {
+		link, _, _ := strings.Cut(r.Request.URL.String(), "?")
+		ref = link + ".map"
+		prov = output.Provenance{Source: output.SourceJS, Finder: output.FinderMapProbe, Raw: ref}
+	}
+	runner.visitLink(r.Request.AbsoluteURL(ref), r.Request, prov)
+}
+
+// handleSourceMap 从 source map 还原源码，获取其中的链接，设置了 -smd 时将源码写入磁盘
+func (runner *Runner) handleSourceMap(request *colly.Request, data []byte) {
+	sm, err := finder.ParseSourceMap(data)
+	if err != nil {
+		log.WithField("error", err).Debug("Parse source map error: ", request.URL.String())
+		return
+	}
+	files := sm.Files()
+	log.Debugf("Recovered %d source files from source map: %s", len(files), request.URL.String())
+
+	if dir := runner.options.SourceMapDir; dir != "" && len(files) > 0 {
+		// 不同主机的源码分开保存
+		dir = filepath.Join(dir, strings.ReplaceAll(request.URL.Host, ":", "_"))
+		if err := finder.DumpSourceFiles(dir, files); err != nil {
+			log.Errorf("Dump source files error: %s", err)
+		}
+	}
+
+	if runner.options.secrets != nil {
+		for _, f := range files {
+			// 使用 URL 片段标记源文件在 source map 中的路径
+			runner.scanSecrets(request.URL.String()+"#"+f.Path, []byte(f.Content), request)
+		}
+	}
+
+	endpoints := finder.FindLinksFromSourceMap(sm)
+	log.Debugf("Found %d links from source map: %s", len(endpoints), request.URL.String())
+	for _, ep := range endpoints {
+		link := runner.resolveEndpoint(request, ep)
+		runner.visitLink(link, request, output.Provenance{Source: output.SourceSourceMap, Finder: output.FinderLinkRegex, Raw: ep})
+	}
+}
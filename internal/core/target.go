@@ -0,0 +1,91 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/zrquan/gatherer/pkg/util"
+)
+
+// target 是一个爬取目标，记录其作用域和统计信息
+type target struct {
+	URL        string
+	root       string // scheme://host/
+	hostname   string
+	subdomains *regexp.Regexp // 允许访问子域名时使用
+
+	visited int64
+	errors  int64
+}
+
+func newTarget(rawURL string, visitSubdomains bool) (*target, error) {
+	if !util.IsAbsoluteURL(rawURL) {
+		return nil, fmt.Errorf("invalid target URL: %s", rawURL)
+	}
+	u, _ := url.Parse(rawURL)
+	t := &target{
+		URL:      rawURL,
+		root:     fmt.Sprintf("%s://%s/", u.Scheme, u.Host),
+		hostname: u.Hostname(),
+	}
+	if visitSubdomains {
+		filter, err := util.FilterSubdomains(t.hostname)
+		if err != nil {
+			return nil, err
+		}
+		t.subdomains = filter
+	}
+	return t, nil
+}
+
+// inScope 判断链接是否属于该目标的作用域
+func (t *target) inScope(link string) bool {
+	if t.subdomains != nil {
+		return t.subdomains.MatchString(link)
+	}
+	hostname, err := util.ExtractHostname(link)
+	if err != nil {
+		return false
+	}
+	return hostname == t.hostname
+}
+
+// readTargets 从文件中读取目标列表，path 为 - 时从标准输入读取
+func readTargets(path string) ([]string, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var targets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	return targets, scanner.Err()
+}
+
+// stdinPiped 判断标准输入是否为管道或文件
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/finder"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// discoverHost 第一次收到主机的响应时请求该主机的元数据文件，设置了 -gql、-apidoc 时还会探测 GraphQL 接口和 API 文档的常见路径。
+// 请求与目标一样从深度 1 开始，不受 -dep 的影响
+func (runner *Runner) discoverHost(request *colly.Request) {
+	opts := runner.options
+	if !opts.WellKnown && !opts.GraphQL && !opts.APIDoc {
+		return
+	}
+	origin := request.URL.Scheme + "://" + request.URL.Host
+	if !runner.hosts.Add(origin) {
+		return
+	}
+	if opts.GraphQL {
+		for _, path := range finder.GraphQLPaths {
+			runner.probeGraphQL(origin+path, request, output.Provenance{Source: output.SourceGraphQL, Raw: path})
+		}
+	}
+	if opts.APIDoc {
+		for _, path := range finder.APIDocPaths {
+			runner.requestFile(origin+path, request, output.Provenance{Source: output.SourceSwagger, Finder: output.FinderAPIDoc, Raw: path})
+		}
+	}
+	if !opts.WellKnown {
+		return
+	}
+	for _, f := range finder.WellKnownFiles {
+		runner.requestFile(origin+f.Path, request, output.Provenance{Source: output.SourceWellKnown, Finder: output.FinderWellKnown, Raw: f.Path})
+	}
+}
+
+// requestFile 从深度 1 请求主机上的文件，跳过已经访问过的和作用域外的链接
+func (runner *Runner) requestFile(link string, request *colly.Request, prov output.Provenance) {
+	if runner.urlSet.Contains(link) || !runner.inScope(runner.targetOf(request.Ctx), link) {
+		return
+	}
+	ctx := newContext(request, prov)
+	runner.track(ctx, "GET", link, 1, nil, nil)
+	if err := runner.collector.Request("GET", link, nil, ctx, nil); err != nil {
+		runner.untrack(ctx)
+	}
+}
+
+// findWellKnownLinks 按文件格式解析元数据文件，访问作用域内的链接，作用域外的主机作为资产输出
+func (runner *Runner) findWellKnownLinks(r *colly.Response) {
+	links, err := finder.FindLinksFromWellKnown(r.Request.URL.Path, r.Body)
+	if err != nil {
+		log.WithField("error", err).Debug("Parse metadata file error: ", r.Request.URL.String())
+		return
+	}
+	t := runner.targetOf(r.Ctx)
+	for _, link := range links {
+		prov := output.Provenance{Source: output.SourceWellKnown, Finder: output.FinderWellKnown, Raw: link}
+		if abs := r.Request.AbsoluteURL(link); runner.inScope(t, abs) {
+			runner.visitLink(abs, r.Request, prov)
+		} else {
+			runner.writeAsset(abs, r.Request, prov)
+		}
+	}
+}
+
+// missingFile 判断猜测的元数据文件、API 文档或 GraphQL 接口是否不存在。除了 404，通配路由还会用首页等 HTML 页面响应任意路径，
+// 而这些文件都不是 HTML 格式。GraphQL 接口只有响应是 GraphQL 格式时才存在
+func missingFile(r *colly.Response) bool {
+	f := r.Ctx.Get("finder")
+	if f == output.FinderIntrospect && r.Ctx.Get("source") == string(output.SourceGraphQL) {
+		return !finder.IsGraphQLResponse(r.Body)
+	}
+	if f != output.FinderWellKnown && f != output.FinderAPIDoc {
+		return false
+	}
+	if r.StatusCode == 404 || r.Headers != nil && strings.Contains(r.Headers.Get("Content-Type"), "html") {
+		return true
+	}
+	head := bytes.ToLower(bytes.TrimSpace(r.Body[:min(len(r.Body), 512)]))
+	return bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<html"))
+}
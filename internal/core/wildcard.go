@@ -0,0 +1,118 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zrquan/gatherer/pkg/filter"
+	"github.com/zrquan/gatherer/pkg/output"
+)
+
+// 每次最多读取的响应体大小，基线只需要长度、词数和标题
+const maxProbeBodySize = 1 << 20
+
+// learnWildcard 在使用字典访问 link 之前，向同一目录下随机且不存在的路径发送请求，学习通配响应的基线。
+// 探测失败时也记录为已学习，每个目录和扩展名只探测一次
+func (runner *Runner) learnWildcard(link, target string) {
+	wf := runner.wildcard
+	if !runner.options.policy.Allow("GET") {
+		return
+	}
+	key, _, err := filter.ParseWildcardKey(link)
+	if err != nil || wf.Learned(key) {
+		return
+	}
+
+	var (
+		names   []string
+		samples []*filter.Sample
+	)
+	for i := 0; i < runner.options.WildcardProbes; i++ {
+		name := randomName()
+		sample, err := runner.probe(key.ProbeURL(name))
+		if err != nil {
+			log.WithField("error", err).Debug("Wildcard probe failed: ", key.ProbeURL(name))
+			wf.Learn(key, nil, nil)
+			return
+		}
+		names = append(names, name)
+		samples = append(samples, sample)
+	}
+
+	if sig := wf.Learn(key, names, samples); sig != nil {
+		runner.options.writer.Write(&output.Result{
+			URL:    key.String(),
+			Method: "GET",
+			Status: sig.Status,
+			Source: output.SourceWordlist,
+			Finder: output.FinderWordlist,
+			Raw:    sig.String(),
+			Depth:  1,
+			Target: target,
+			Type:   output.TypeWildcard,
+			Wildcard: &output.Wildcard{
+				Status:    sig.Status,
+				MinLength: sig.MinLength,
+				MaxLength: sig.MaxLength,
+				MinWords:  sig.MinWords,
+				MaxWords:  sig.MaxWords,
+				Title:     sig.Title,
+				Location:  sig.FinalURL,
+			},
+		})
+	}
+}
+
+// probe 使用与 collector 相同的代理、请求头和重定向策略发送探测请求
+func (runner *Runner) probe(link string) (*filter.Sample, error) {
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", runner.collector.UserAgent)
+	for _, h := range runner.options.Headers {
+		if k, v, ok := strings.Cut(h, ":"); ok {
+			req.Header.Set(strings.TrimSpace(k), strings.TrimSpace(v))
+		}
+	}
+
+	resp, err := runner.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodySize))
+	if err != nil {
+		return nil, err
+	}
+	return &filter.Sample{
+		Status:   resp.StatusCode,
+		Body:     body,
+		FinalURL: resp.Request.URL.String(),
+	}, nil
+}
+
+func newProbeClient(opts *Options) *http.Client {
+	client := &http.Client{
+		Transport: newTransport(opts),
+		Timeout:   time.Duration(opts.Timeout) * time.Second,
+	}
+	if opts.NoRedirect {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+func randomName() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
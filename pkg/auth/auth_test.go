@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarker(t *testing.T) {
+	config := &Config{LoggedOut: &Marker{Status: []int{401}, Location: `/login`, Body: `(?i)please sign in`}}
+	if err := config.compile(); err != nil {
+		t.Fatal(err)
+	}
+	m := config.LoggedOut
+
+	tests := []struct {
+		status   int
+		location string
+		body     string
+		want     bool
+	}{
+		{401, "", "", true},
+		{302, "http://example.com/login?next=/admin", "", true},
+		{200, "http://example.com/", "Please sign in to continue", true},
+		{200, "http://example.com/", "welcome", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.status, tt.location, []byte(tt.body)); got != tt.want {
+			t.Errorf("Match(%d, %q, %q) = %v, want %v", tt.status, tt.location, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestJar(t *testing.T) {
+	u, _ := url.Parse("http://example.com/app/")
+	jar := NewJar()
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "abc", Path: "/"}})
+
+	file := filepath.Join(t.TempDir(), "cookies.json")
+	if err := jar.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewJar()
+	if err := loaded.Load(file); err != nil {
+		t.Fatal(err)
+	}
+	cookies := loaded.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Value != "abc" {
+		t.Errorf("Cookies() = %v", cookies)
+	}
+}
+
+func TestFormLogin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<form id="search"><input name="q"></form>
+<form action="/session" method="post">
+  <input type="hidden" name="csrf" value="token">
+  <input name="username"><input type="password" name="password">
+  <input type="submit" name="commit" value="Sign in">
+</form>`))
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("csrf") != "token" || r.PostForm.Get("password") != "secret" || r.PostForm.Has("commit") {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+		http.Redirect(w, r, "/home", http.StatusFound)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("welcome"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	config := &Config{
+		Login:     &Login{URL: ts.URL + "/login", Fields: map[string]string{"username": "admin", "password": "secret"}},
+		LoggedOut: &Marker{Location: `/login$`},
+	}
+	if err := config.compile(); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSession(config, http.DefaultTransport, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Generation() != 1 {
+		t.Errorf("Generation() = %d, want 1", s.Generation())
+	}
+	u, _ := url.Parse(ts.URL)
+	if cookies := s.Jar().Cookies(u); len(cookies) != 1 || cookies[0].Value != "ok" {
+		t.Errorf("Cookies() = %v", cookies)
+	}
+
+	// 其他请求已经重新登录过时不再登录
+	if err := s.Refresh(0); err != nil || s.Generation() != 1 {
+		t.Errorf("Refresh(0) = %v, generation %d", err, s.Generation())
+	}
+}
+
+func TestFormLoginSamePage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			r.ParseForm()
+			if r.PostForm.Get("password") != "secret" {
+				http.Redirect(w, r, "/login", http.StatusFound)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "ok", Path: "/"})
+			w.Write([]byte("welcome"))
+			return
+		}
+		w.Write([]byte(`<form method="post"><input name="username"><input type="password" name="password"></form>`))
+	}))
+	defer ts.Close()
+
+	for password, ok := range map[string]bool{"secret": true, "wrong": false} {
+		config := &Config{
+			Login:     &Login{URL: ts.URL + "/login", Fields: map[string]string{"username": "admin", "password": password}},
+			LoggedOut: &Marker{Location: `/login$`},
+		}
+		if err := config.compile(); err != nil {
+			t.Fatal(err)
+		}
+		s, err := NewSession(config, http.DefaultTransport, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Login(); (err == nil) != ok {
+			t.Errorf("Login() with password %q = %v", password, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	log "github.com/sirupsen/logrus"
+)
+
+// 浏览器登录的超时时间
+const browserLoginTimeout = 60 * time.Second
+
+// browserLogin 在浏览器中按顺序执行录制的登录步骤，完成后将浏览器的 cookie 导入 jar
+func (s *Session) browserLogin(l *Login) error {
+	if s.browser == nil {
+		return errors.New("browser login requires headless Chrome")
+	}
+	page, err := s.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return err
+	}
+	defer page.Close()
+
+	steps := l.Steps
+	if l.URL != "" && steps[0].Action != "navigate" {
+		steps = append([]*Step{{Action: "navigate", Value: l.URL}}, steps...)
+	}
+
+	err = rod.Try(func() {
+		p := page.Timeout(browserLoginTimeout)
+		for _, step := range steps {
+			switch step.Action {
+			case "navigate":
+				p.MustNavigate(step.Value).MustWaitLoad()
+			case "input":
+				p.MustElement(step.Selector).MustSelectAllText().MustInput(step.Value)
+			case "click":
+				p.MustElement(step.Selector).MustClick()
+			case "wait":
+				p.MustElement(step.Selector)
+			}
+		}
+		p.MustWaitLoad()
+	})
+	if err != nil {
+		return fmt.Errorf("browser login: %w", err)
+	}
+	return s.syncFromBrowser()
+}
+
+// syncToBrowser 将 jar 中的 cookie 设置到浏览器
+func (s *Session) syncToBrowser() {
+	if s.browser == nil {
+		return
+	}
+	var params []*proto.NetworkCookieParam
+	for _, e := range s.jar.all() {
+		c := e.Cookie
+		param := &proto.NetworkCookieParam{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+		if c.Domain == "" {
+			// 仅限当前主机的 cookie 通过 URL 设置
+			param.URL = e.URL
+		}
+		if param.Path == "" {
+			param.Path = "/"
+		}
+		if !c.Expires.IsZero() {
+			param.Expires = proto.TimeSinceEpoch(c.Expires.Unix())
+		}
+		params = append(params, param)
+	}
+	if len(params) == 0 {
+		return
+	}
+	if err := s.browser.SetCookies(params); err != nil {
+		log.Warn("Set browser cookies error: ", err)
+	}
+}
+
+// syncFromBrowser 将浏览器中的 cookie 导入 jar
+func (s *Session) syncFromBrowser() error {
+	cookies, err := s.browser.GetCookies()
+	if err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+		u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(c.Domain, "."), Path: c.Path}
+		cookie := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}
+		if strings.HasPrefix(c.Domain, ".") {
+			cookie.Domain = c.Domain
+		}
+		if !c.Session {
+			cookie.Expires = c.Expires.Time()
+		}
+		s.jar.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step 是浏览器登录中的一步操作
+type Step struct {
+	Action   string `yaml:"action"`   // navigate, input, click, wait
+	Selector string `yaml:"selector"` // input、click、wait 使用的 CSS 选择器
+	Value    string `yaml:"value"`    // navigate 的 URL 或 input 输入的内容
+}
+
+// Login 描述登录方式，设置了 steps 时使用浏览器登录，否则提交登录表单
+type Login struct {
+	URL    string            `yaml:"url"`    // 登录页面
+	Form   string            `yaml:"form"`   // 登录表单的 CSS 选择器，默认为包含密码框的表单
+	Action string            `yaml:"action"` // 表单提交地址，默认使用表单的 action
+	Method string            `yaml:"method"` // 表单提交方法，默认使用表单的 method
+	Fields map[string]string `yaml:"fields"` // 需要填写的表单字段，其他字段（如 CSRF token）使用页面中的值
+	Steps  []*Step           `yaml:"steps"`
+}
+
+// Marker 描述会话失效时的响应特征，满足任意一项即认为已退出登录
+type Marker struct {
+	Status   []int  `yaml:"status"`   // 状态码，如 401
+	Location string `yaml:"location"` // 匹配重定向地址的正则表达式，如 /login
+	Body     string `yaml:"body"`     // 匹配响应体的正则表达式
+
+	location *regexp.Regexp
+	body     *regexp.Regexp
+}
+
+// Config 是登录配置文件的格式，支持 YAML 和 JSON
+type Config struct {
+	Login     *Login  `yaml:"login"`
+	LoggedOut *Marker `yaml:"logged_out"`
+	CookieJar string  `yaml:"cookie_jar"` // 保存 cookie 的文件，下次运行时继续使用
+}
+
+// LoadConfig 从 YAML 或 JSON 文件中加载登录配置
+func LoadConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid auth file: %w", err)
+	}
+	if err := config.compile(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (config *Config) compile() error {
+	if l := config.Login; l != nil {
+		if l.URL == "" && len(l.Steps) == 0 {
+			return errors.New("login url or steps is required in auth file")
+		}
+		for _, s := range l.Steps {
+			switch s.Action {
+			case "navigate", "input", "click", "wait":
+			default:
+				return fmt.Errorf("unknown login step: %s", s.Action)
+			}
+		}
+	}
+	if m := config.LoggedOut; m != nil {
+		if m.Location != "" {
+			regex, err := regexp.Compile(m.Location)
+			if err != nil {
+				return fmt.Errorf("invalid location regex in auth file: %s", m.Location)
+			}
+			m.location = regex
+		}
+		if m.Body != "" {
+			regex, err := regexp.Compile(m.Body)
+			if err != nil {
+				return fmt.Errorf("invalid body regex in auth file: %s", m.Body)
+			}
+			m.body = regex
+		}
+	}
+	return nil
+}
+
+// Match 判断响应是否表示会话已经失效，location 为重定向地址
+func (m *Marker) Match(status int, location string, body []byte) bool {
+	if m == nil {
+		return false
+	}
+	if slices.Contains(m.Status, status) {
+		return true
+	}
+	if m.location != nil && location != "" && m.location.MatchString(location) {
+		return true
+	}
+	return m.body != nil && m.body.Match(body)
+}
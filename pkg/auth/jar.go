@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// entry 是一个已保存的 cookie 及其来源 URL
+type entry struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// Jar 是可以持久化的 cookie jar，在标准库 cookiejar 的基础上记录所有设置过的 cookie
+type Jar struct {
+	mutex   sync.Mutex
+	jar     *cookiejar.Jar
+	entries map[string]*entry
+}
+
+func NewJar() *Jar {
+	jar, _ := cookiejar.New(nil)
+	return &Jar{
+		jar:     jar,
+		entries: make(map[string]*entry),
+	}
+}
+
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.jar.SetCookies(u, cookies)
+	for _, c := range cookies {
+		key := cookieKey(u, c)
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(time.Now())) {
+			delete(j.entries, key)
+			continue
+		}
+		j.entries[key] = &entry{URL: u.String(), Cookie: c}
+	}
+}
+
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Len 返回保存的 cookie 数量
+func (j *Jar) Len() int {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return len(j.entries)
+}
+
+// Save 将 cookie 写入文件
+func (j *Jar) Save(file string) error {
+	data, err := json.MarshalIndent(j.all(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o600)
+}
+
+// Load 从文件中读取 cookie，已过期的 cookie 会被忽略
+func (j *Jar) Load(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var entries []*entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		u, err := url.Parse(e.URL)
+		if err != nil || e.Cookie == nil {
+			continue
+		}
+		j.SetCookies(u, []*http.Cookie{e.Cookie})
+	}
+	return nil
+}
+
+// all 返回所有保存的 cookie
+func (j *Jar) all() []*entry {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	entries := make([]*entry, 0, len(j.entries))
+	for _, e := range j.entries {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func cookieKey(u *url.URL, c *http.Cookie) string {
+	domain := c.Domain
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	return domain + ";" + c.Path + ";" + c.Name
+}
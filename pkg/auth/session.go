@@ -0,0 +1,274 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// 登录页面最多读取的大小
+	maxLoginBodySize = 4 << 20
+	// 登录失败后，在这段时间内不再尝试重新登录
+	loginRetryInterval = 10 * time.Second
+)
+
+// Session 管理登录状态，colly、探测请求和浏览器共享同一个 cookie jar
+type Session struct {
+	config  *Config
+	jar     *Jar
+	client  *http.Client
+	browser *rod.Browser
+	header  http.Header
+
+	mutex      sync.Mutex
+	generation int64
+	failedAt   time.Time
+	failure    error
+}
+
+// NewSession 创建会话，配置了 cookie_jar 且文件存在时加载之前保存的 cookie
+func NewSession(config *Config, transport http.RoundTripper, header http.Header, browser *rod.Browser) (*Session, error) {
+	jar := NewJar()
+	if config.CookieJar != "" {
+		if err := jar.Load(config.CookieJar); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("load cookie jar: %w", err)
+		}
+	}
+	s := &Session{
+		config: config,
+		jar:    jar,
+		client: &http.Client{
+			Transport: transport,
+			Jar:       jar,
+			Timeout:   30 * time.Second,
+		},
+		browser: browser,
+		header:  header,
+	}
+	if jar.Len() > 0 {
+		log.WithField("cookies", jar.Len()).Info("Loaded cookie jar: ", config.CookieJar)
+		s.syncToBrowser()
+	}
+	return s, nil
+}
+
+func (s *Session) Jar() *Jar {
+	return s.jar
+}
+
+// Generation 返回当前登录的序号，每次重新登录后加一
+func (s *Session) Generation() int64 {
+	return atomic.LoadInt64(&s.generation)
+}
+
+// CanLogin 判断是否配置了登录方式
+func (s *Session) CanLogin() bool {
+	return s.config.Login != nil
+}
+
+// LoggedOut 判断响应是否表示会话已经失效
+func (s *Session) LoggedOut(status int, location string, body []byte) bool {
+	return s.config.LoggedOut.Match(status, location, body)
+}
+
+// Login 执行登录，成功后保存 cookie 并同步到浏览器
+func (s *Session) Login() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.login()
+}
+
+// Refresh 在会话失效后重新登录，generation 为发出失效请求时的登录序号，
+// 如果其他请求已经重新登录过则直接返回
+func (s *Session) Refresh(generation int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if generation != s.Generation() {
+		return nil
+	}
+	// 避免登录失败后每个失效的响应都触发一次登录
+	if s.failure != nil && time.Since(s.failedAt) < loginRetryInterval {
+		return s.failure
+	}
+	return s.login()
+}
+
+// Save 将 cookie 写入配置的文件
+func (s *Session) Save() error {
+	if s.config.CookieJar == "" {
+		return nil
+	}
+	return s.jar.Save(s.config.CookieJar)
+}
+
+func (s *Session) login() error {
+	l := s.config.Login
+	if l == nil {
+		return errors.New("login is not configured")
+	}
+
+	var err error
+	if len(l.Steps) > 0 {
+		err = s.browserLogin(l)
+	} else {
+		err = s.formLogin(l)
+	}
+	if err != nil {
+		s.failedAt, s.failure = time.Now(), err
+		return err
+	}
+	s.failure = nil
+
+	atomic.AddInt64(&s.generation, 1)
+	if err := s.Save(); err != nil {
+		log.Warn("Save cookie jar error: ", err)
+	}
+	s.syncToBrowser()
+	log.WithField("cookies", s.jar.Len()).Info("Logged in: ", l.URL)
+	return nil
+}
+
+// formLogin 打开登录页面，使用页面中登录表单的默认值（如 CSRF token）和配置的字段提交表单
+func (s *Session) formLogin(l *Login) error {
+	page, err := url.Parse(l.URL)
+	if err != nil {
+		return err
+	}
+	resp, body, err := s.do("GET", page.String(), nil)
+	if err != nil {
+		return fmt.Errorf("open login page: %w", err)
+	}
+	page = resp.Request.URL
+
+	action, method := page.String(), "POST"
+	values := url.Values{}
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body)); err == nil {
+		if form := findForm(doc, l.Form); form != nil {
+			if a, ok := form.Attr("action"); ok && a != "" {
+				if u, err := page.Parse(a); err == nil {
+					action = u.String()
+				}
+			}
+			if m, ok := form.Attr("method"); ok && m != "" {
+				method = strings.ToUpper(m)
+			}
+			values = formValues(form)
+		}
+	}
+	for k, v := range l.Fields {
+		values.Set(k, v)
+	}
+	if l.Action != "" {
+		u, err := page.Parse(l.Action)
+		if err != nil {
+			return err
+		}
+		action = u.String()
+	}
+	if l.Method != "" {
+		method = strings.ToUpper(l.Method)
+	}
+
+	if method == "GET" {
+		u, _ := url.Parse(action)
+		u.RawQuery = values.Encode()
+		resp, body, err = s.do(method, u.String(), nil)
+	} else {
+		resp, body, err = s.do(method, action, strings.NewReader(values.Encode()))
+	}
+	if err != nil {
+		return fmt.Errorf("submit login form: %w", err)
+	}
+	// 只有发生跳转时最终地址才能说明登录结果，表单提交到登录页本身时不匹配 location
+	location := ""
+	if resp.Request.Response != nil {
+		location = resp.Request.URL.String()
+	}
+	if s.LoggedOut(resp.StatusCode, location, body) {
+		return errors.New("login failed: still logged out after submitting the form")
+	}
+	return nil
+}
+
+func (s *Session) do(method, link string, data io.Reader) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(method, link, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxLoginBodySize))
+	return resp, body, err
+}
+
+// findForm 查找登录表单，未指定选择器时使用第一个包含密码框的表单
+func findForm(doc *goquery.Document, selector string) *goquery.Selection {
+	var form *goquery.Selection
+	if selector != "" {
+		form = doc.Find(selector).First()
+	} else {
+		form = doc.Find("form").FilterFunction(func(_ int, s *goquery.Selection) bool {
+			return s.Find(`input[type="password"]`).Length() > 0
+		}).First()
+		if form.Length() == 0 {
+			form = doc.Find("form").First()
+		}
+	}
+	if form.Length() == 0 {
+		return nil
+	}
+	return form
+}
+
+// formValues 返回表单中各字段的默认值
+func formValues(form *goquery.Selection) url.Values {
+	values := url.Values{}
+	form.Find("input[name]").Each(func(_ int, input *goquery.Selection) {
+		name, _ := input.Attr("name")
+		value, _ := input.Attr("value")
+		switch strings.ToLower(input.AttrOr("type", "text")) {
+		case "submit", "button", "image", "reset", "file":
+			return
+		case "checkbox", "radio":
+			if _, checked := input.Attr("checked"); !checked {
+				return
+			}
+		}
+		values.Add(name, value)
+	})
+	form.Find("textarea[name]").Each(func(_ int, textarea *goquery.Selection) {
+		values.Add(textarea.AttrOr("name", ""), textarea.Text())
+	})
+	form.Find("select[name]").Each(func(_ int, sel *goquery.Selection) {
+		option := sel.Find("option[selected]").First()
+		if option.Length() == 0 {
+			option = sel.Find("option").First()
+		}
+		if option.Length() > 0 {
+			values.Add(sel.AttrOr("name", ""), option.AttrOr("value", option.Text()))
+		}
+	})
+	return values
+}
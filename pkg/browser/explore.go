@@ -0,0 +1,288 @@
+package browser
+
+import (
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/zrquan/gatherer/pkg/finder"
+)
+
+// 可能造成破坏或退出登录的元素不点击
+var dangerousText = regexp.MustCompile(`(?i)delete|remove|destroy|drop|log ?out|sign ?out|删除|移除|退出|注销`)
+
+// 返回当前页面的 URL 和所有可以点击的元素
+const candidatesJS = `() => {
+	const selector = el => {
+		const parts = [];
+		for (; el && el.nodeType === 1 && el !== document.body; el = el.parentElement) {
+			let i = 1;
+			for (let s = el.previousElementSibling; s; s = s.previousElementSibling) {
+				if (s.tagName === el.tagName) i++;
+			}
+			parts.unshift(el.tagName.toLowerCase() + ':nth-of-type(' + i + ')');
+		}
+		return 'body > ' + parts.join(' > ');
+	};
+	const visible = el => {
+		const r = el.getBoundingClientRect();
+		return r.width > 0 && r.height > 0 && getComputedStyle(el).visibility !== 'hidden';
+	};
+	const items = [];
+	const query = 'a, button, summary, input[type=submit], input[type=button], [onclick], [role=button], [role=link], [role=menuitem], [role=tab]';
+	for (const el of document.querySelectorAll(query)) {
+		if (el === document.body || el.disabled || !visible(el)) continue;
+		if (el.tagName === 'A') {
+			const href = el.getAttribute('href') || '';
+			if (el.target === '_blank' || /^(mailto|tel):/i.test(href)) continue;
+			if (el.href && el.origin !== location.origin && !/^(#|javascript:)/i.test(href)) continue;
+		}
+		// 链接的地址或者提交表单的地址
+		const form = el.closest('form');
+		let target = el.tagName === 'A' ? el.href : '';
+		if (!target && form) {
+			target = new URL(el.getAttribute('formaction') || form.getAttribute('action') || '', location.href).href;
+		}
+		items.push({
+			selector: selector(el),
+			text: (el.innerText || el.value || '').trim().slice(0, 50),
+			form: !!form,
+			target: target,
+		});
+	}
+	return {url: location.href, items: items};
+}`
+
+// 按输入框类型填写元素所在的表单
+const fillJS = `(selector, values) => {
+	const el = document.querySelector(selector);
+	const form = el && el.closest('form');
+	if (!form) return;
+	for (const input of form.querySelectorAll('input, textarea, select')) {
+		if (input.disabled || input.readOnly) continue;
+		const type = (input.type || 'text').toLowerCase();
+		if (['hidden', 'submit', 'button', 'reset', 'image', 'file'].includes(type)) continue;
+		if (type === 'checkbox' || type === 'radio') {
+			if (!input.checked) input.click();
+			continue;
+		}
+		if (input.tagName === 'SELECT') {
+			if (input.options.length > 1) input.selectedIndex = 1;
+		} else if (!input.value) {
+			// 通过原型上的 setter 赋值，React 等框架才能感知到变化
+			const desc = Object.getOwnPropertyDescriptor(Object.getPrototypeOf(input), 'value');
+			const value = values[type] || values.text;
+			desc && desc.set ? desc.set.call(input, value) : (input.value = value);
+			input.dispatchEvent(new Event('input', {bubbles: true}));
+		}
+		input.dispatchEvent(new Event('change', {bubbles: true}));
+	}
+}`
+
+const clickJS = `selector => {
+	const el = document.querySelector(selector);
+	if (!el) return false;
+	el.click();
+	return true;
+}`
+
+// Exploration 是一次页面交互的结果
+type Exploration struct {
+	HTML     string     // 页面加载完成后的 HTML
+	Routes   []string   // 交互过程中页面 URL 的变化，如前端路由
+	Requests []*Request // 页面加载和交互过程中发出的请求
+}
+
+// Explorer 在浏览器中点击页面元素、填写表单，发现只能通过交互访问的前端路由和请求。
+// 交互深度、每个页面的操作次数和已经访问过的页面状态限制了探索的范围
+type Explorer struct {
+	pool    *Pool
+	depth   int
+	actions int
+
+	mutex  sync.Mutex
+	states map[uint64]bool
+}
+
+type element struct {
+	Selector string `json:"selector"`
+	Text     string `json:"text"`
+	Form     bool   `json:"form"`
+	Target   string `json:"target"` // 点击后访问的地址，不是链接或表单时为空
+}
+
+// snapshot 是页面在某一时刻的状态
+type snapshot struct {
+	URL      string     `json:"url"`
+	Elements []*element `json:"items"`
+}
+
+// fingerprint 根据 URL 和可以点击的元素计算页面状态的指纹
+func (s *snapshot) fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s.URL))
+	for _, e := range s.Elements {
+		h.Write([]byte{0})
+		h.Write([]byte(e.Selector))
+		h.Write([]byte(e.Text))
+	}
+	return h.Sum64()
+}
+
+// NewExplorer 创建 Explorer，depth 为连续操作的最大次数，actions 为每个页面最多执行的操作次数
+func NewExplorer(pool *Pool, depth, actions int) *Explorer {
+	return &Explorer{
+		pool:    pool,
+		depth:   depth,
+		actions: actions,
+		states:  make(map[uint64]bool),
+	}
+}
+
+// Explore 加载 collector 已经获取到的页面，并按广度优先的顺序与页面交互
+func (e *Explorer) Explore(doc *Document) (*Exploration, error) {
+	t, err := e.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	t.Load(doc)
+
+	result := &Exploration{}
+	root, err := e.restore(t, doc, nil)
+	if err == nil {
+		err = rod.Try(func() {
+			result.HTML = t.page.Timeout(e.pool.timeout).MustHTML()
+		})
+	}
+	if err != nil {
+		e.pool.Put(t, true)
+		return nil, err
+	}
+
+	if e.visit(root.fingerprint()) {
+		e.explore(t, doc, result)
+	}
+	result.Requests = t.Requests()
+	e.pool.Put(t, false)
+	return result, nil
+}
+
+// explore 从初始状态开始，依次点击每个状态中的元素，页面状态发生变化时将新状态加入队列
+func (e *Explorer) explore(t *Tab, doc *Document, result *Exploration) {
+	budget := e.actions
+	queue := [][]*element{nil}
+	for len(queue) > 0 && budget > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		current, err := e.restore(t, doc, path)
+		if err != nil {
+			continue
+		}
+		fp := current.fingerprint()
+		for _, el := range current.Elements {
+			if budget <= 0 {
+				break
+			}
+			if dangerousText.MatchString(el.Text) || !e.allowed(el) {
+				continue
+			}
+			budget--
+
+			after, err := e.act(t, el)
+			if err != nil {
+				if current, err = e.restore(t, doc, path); err != nil {
+					break
+				}
+				continue
+			}
+			if after.URL != current.URL && !slices.Contains(result.Routes, after.URL) {
+				result.Routes = append(result.Routes, after.URL)
+			}
+			if after.fingerprint() == fp {
+				continue
+			}
+			if len(path)+1 < e.depth && e.visit(after.fingerprint()) {
+				queue = append(queue, append(slices.Clone(path), el))
+			}
+			// 页面状态已经改变，回到当前状态后再点击下一个元素
+			if current, err = e.restore(t, doc, path); err != nil {
+				break
+			}
+		}
+	}
+}
+
+// restore 重新加载页面并依次执行 path 中的操作
+func (e *Explorer) restore(t *Tab, doc *Document, path []*element) (*snapshot, error) {
+	err := rod.Try(func() {
+		page := t.page.Timeout(e.pool.timeout)
+		page.MustNavigate(doc.URL).MustWaitLoad()
+		page.WaitRequestIdle(idleDuration, nil, nil, nil)()
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, el := range path {
+		if _, err := e.act(t, el); err != nil {
+			return nil, err
+		}
+	}
+	return e.snapshot(t)
+}
+
+// act 点击元素，元素在表单中时先填写表单，等待网络空闲后返回页面状态
+func (e *Explorer) act(t *Tab, el *element) (*snapshot, error) {
+	err := rod.Try(func() {
+		page := t.page.Timeout(e.pool.timeout)
+		if el.Form {
+			page.MustEval(fillJS, el.Selector, finder.FormValues)
+		}
+		wait := page.WaitRequestIdle(idleDuration, nil, nil, nil)
+		if !page.MustEval(clickJS, el.Selector).Bool() {
+			panic(errors.New("element not found: " + el.Selector))
+		}
+		wait()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.snapshot(t)
+}
+
+func (e *Explorer) snapshot(t *Tab) (*snapshot, error) {
+	var s snapshot
+	err := rod.Try(func() {
+		obj := t.page.Timeout(e.pool.timeout).MustEval(candidatesJS)
+		if err := obj.Unmarshal(&s); err != nil {
+			panic(err)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// allowed 判断元素点击后访问的地址是否在作用域内，比如 scope 文件中 never_visit 的 /logout
+func (e *Explorer) allowed(el *element) bool {
+	if e.pool.scope == nil || !strings.HasPrefix(el.Target, "http") {
+		return true
+	}
+	return e.pool.scope(el.Target)
+}
+
+// visit 记录页面状态，返回该状态是否是第一次访问
+func (e *Explorer) visit(fingerprint uint64) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.states[fingerprint] {
+		return false
+	}
+	e.states[fingerprint] = true
+	return true
+}
@@ -0,0 +1,255 @@
+package browser
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 页面加载完成后，网络空闲多久认为页面已经稳定
+const idleDuration = 500 * time.Millisecond
+
+// Request 是页面加载过程中发出的 XHR、fetch、EventSource 或 WebSocket 请求
+type Request struct {
+	Method string
+	URL    string
+	Type   string // document, xhr, fetch, eventsource, websocket
+	Body   string
+}
+
+// Document 是 collector 已经获取到的页面，浏览器直接使用而不再重新下载
+type Document struct {
+	URL    string
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Pool 是可以复用的浏览器页面池，同时打开的页面数量不超过 size
+type Pool struct {
+	browser *rod.Browser
+	pages   rod.PagePool
+	timeout time.Duration
+	allow   func(method string) bool
+	scope   func(link string) bool
+
+	mutex sync.Mutex
+	tabs  map[*rod.Page]*Tab
+}
+
+// NewPool 创建页面池，allow 不为空时浏览器只发出其允许的请求方法，scope 不为空时只请求作用域内的 URL
+func NewPool(browser *rod.Browser, size int, timeout time.Duration, allow func(method string) bool, scope func(link string) bool) *Pool {
+	return &Pool{
+		browser: browser,
+		pages:   rod.NewPagePool(max(size, 1)),
+		timeout: timeout,
+		allow:   allow,
+		scope:   scope,
+		tabs:    make(map[*rod.Page]*Tab),
+	}
+}
+
+// Get 从池中取出一个页面，池中没有空闲页面时等待
+func (p *Pool) Get() (*Tab, error) {
+	var err error
+	page := p.pages.Get(func() *rod.Page {
+		var page *rod.Page
+		page, err = p.newTab()
+		return page
+	})
+	if page == nil {
+		p.pages.Put(nil)
+		return nil, err
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.tabs[page], nil
+}
+
+// Put 将页面放回池中，broken 为 true 时关闭页面，下次使用时重新创建
+func (p *Pool) Put(t *Tab, broken bool) {
+	if broken {
+		p.mutex.Lock()
+		delete(p.tabs, t.page)
+		p.mutex.Unlock()
+		t.close()
+		p.pages.Put(nil)
+		return
+	}
+	t.reset(nil)
+	// 离开当前页面，停止页面中仍在运行的脚本
+	t.page.Navigate("about:blank")
+	p.pages.Put(t.page)
+}
+
+// Close 等待所有页面使用完毕后关闭它们
+func (p *Pool) Close() {
+	p.pages.Cleanup(func(page *rod.Page) {
+		p.mutex.Lock()
+		t := p.tabs[page]
+		delete(p.tabs, page)
+		p.mutex.Unlock()
+		if t != nil {
+			t.close()
+		}
+	})
+}
+
+// Render 在浏览器中加载 collector 已经获取到的页面，返回渲染后的 HTML 和页面发出的请求
+func (p *Pool) Render(doc *Document) (string, []*Request, error) {
+	t, err := p.Get()
+	if err != nil {
+		return "", nil, err
+	}
+
+	t.Load(doc)
+	var html string
+	err = rod.Try(func() {
+		page := t.page.Timeout(p.timeout)
+		page.MustNavigate(doc.URL).MustWaitLoad()
+		page.WaitRequestIdle(idleDuration, nil, nil, nil)()
+		html = page.MustHTML()
+	})
+	requests := t.reset(nil)
+	p.Put(t, err != nil)
+	if err != nil {
+		return "", requests, err
+	}
+	return html, requests, nil
+}
+
+func (p *Pool) newTab() (*rod.Page, error) {
+	page, err := p.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return nil, err
+	}
+	t := &Tab{page: page, allow: p.allow, scope: p.scope, seen: make(map[string]bool)}
+
+	t.router = page.HijackRequests()
+	if err := t.router.Add("*", "", t.hijack); err != nil {
+		page.Close()
+		return nil, err
+	}
+	go t.router.Run()
+
+	// 大多数 Chrome 版本中 WebSocket 握手不经过 Fetch 拦截，无法按作用域和请求方法策略阻止，只能通过 Network 事件记录。
+	// collector 只把这些地址作为发现的链接输出，不会访问
+	if err := (proto.NetworkEnable{}).Call(page); err == nil {
+		go page.EachEvent(func(e *proto.NetworkWebSocketCreated) {
+			t.record(&Request{Method: "GET", URL: e.URL, Type: "websocket"})
+		})()
+	}
+	// 自动取消 alert、confirm 等对话框，避免页面阻塞
+	go page.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+		_ = proto.PageHandleJavaScriptDialog{Accept: false}.Call(page)
+	})()
+
+	p.mutex.Lock()
+	p.tabs[page] = t
+	p.mutex.Unlock()
+	return page, nil
+}
+
+// Tab 是页面池中的一个页面，拦截并记录页面发出的请求
+type Tab struct {
+	page   *rod.Page
+	router *rod.HijackRouter
+	allow  func(method string) bool
+	scope  func(link string) bool
+
+	mutex    sync.Mutex
+	doc      *Document
+	requests []*Request
+	seen     map[string]bool
+}
+
+// Page 返回底层的 rod 页面
+func (t *Tab) Page() *rod.Page {
+	return t.page
+}
+
+// Load 设置下一次导航时直接使用的页面内容
+func (t *Tab) Load(doc *Document) {
+	t.reset(doc)
+}
+
+// Requests 返回并清空已经记录的请求
+func (t *Tab) Requests() []*Request {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	requests := t.requests
+	t.requests = nil
+	return requests
+}
+
+func (t *Tab) reset(doc *Document) []*Request {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	requests := t.requests
+	t.doc = doc
+	t.requests = nil
+	t.seen = make(map[string]bool)
+	return requests
+}
+
+func (t *Tab) record(r *Request) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	key := r.Method + " " + r.URL + " " + r.Body
+	if t.seen[key] {
+		return
+	}
+	t.seen[key] = true
+	t.requests = append(t.requests, r)
+}
+
+func (t *Tab) hijack(h *rod.Hijack) {
+	t.mutex.Lock()
+	doc := t.doc
+	t.mutex.Unlock()
+
+	method := h.Request.Method()
+	switch h.Request.Type() {
+	case proto.NetworkResourceTypeDocument:
+		// 使用 collector 获取到的页面内容，避免重复下载
+		if doc != nil && method == "GET" && h.Request.URL().String() == doc.URL {
+			if ct := doc.Header.Get("Content-Type"); ct != "" {
+				h.Response.SetHeader("Content-Type", ct)
+			}
+			h.Response.Payload().ResponseCode = doc.Status
+			h.Response.SetBody(doc.Body)
+			return
+		}
+		// 页面跳转或 iframe
+		if doc != nil {
+			t.record(&Request{Method: method, URL: h.Request.URL().String(), Type: "document", Body: h.Request.Body()})
+		}
+	case proto.NetworkResourceTypeXHR, proto.NetworkResourceTypeFetch, proto.NetworkResourceTypeEventSource, proto.NetworkResourceTypeWebSocket:
+		t.record(&Request{
+			Method: method,
+			URL:    h.Request.URL().String(),
+			Type:   strings.ToLower(string(h.Request.Type())),
+			Body:   h.Request.Body(),
+		})
+	case proto.NetworkResourceTypeImage, proto.NetworkResourceTypeMedia, proto.NetworkResourceTypeFont:
+		// 不加载与链接收集无关的资源
+		h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+		return
+	}
+
+	// 作用域外的请求和不允许的请求方法只记录不发送
+	if t.allow != nil && !t.allow(method) || t.scope != nil && !t.scope(h.Request.URL().String()) {
+		h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+		return
+	}
+	h.ContinueRequest(&proto.FetchContinueRequest{})
+}
+
+func (t *Tab) close() {
+	t.router.Stop()
+	t.page.Close()
+}
@@ -0,0 +1,147 @@
+package browser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+func TestPoolRender(t *testing.T) {
+	if _, found := launcher.LookPath(); !found {
+		t.Skip("headless Chrome is not available")
+	}
+	l := launcher.New().Headless(true).MustLaunch()
+	b := rod.New().ControlURL(l).MustConnect()
+	defer b.MustClose()
+
+	var documents int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&documents, 1)
+	})
+	mux.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	pool := NewPool(b, 1, 10*time.Second, nil, nil)
+	defer pool.Close()
+	doc := &Document{
+		URL:    ts.URL + "/",
+		Status: 200,
+		Header: http.Header{"Content-Type": []string{"text/html"}},
+		Body:   []byte(`<html><body><script>fetch("/api/users", {method: "POST", body: "{}"})</script></body></html>`),
+	}
+	for i := 0; i < 2; i++ {
+		_, requests, err := pool.Render(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(requests) != 1 || requests[0].Method != "POST" || requests[0].URL != ts.URL+"/api/users" || requests[0].Type != "fetch" {
+			t.Errorf("unexpected requests: %v", requests)
+		}
+	}
+	// 页面内容由 collector 提供，不会重新下载
+	if n := atomic.LoadInt64(&documents); n != 0 {
+		t.Errorf("document downloaded %d times", n)
+	}
+}
+
+func TestPoolScope(t *testing.T) {
+	if _, found := launcher.LookPath(); !found {
+		t.Skip("headless Chrome is not available")
+	}
+	l := launcher.New().Headless(true).MustLaunch()
+	b := rod.New().ControlURL(l).MustConnect()
+	defer b.MustClose()
+
+	var logouts int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&logouts, 1)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	pool := NewPool(b, 1, 10*time.Second, nil, func(link string) bool { return !strings.HasSuffix(link, "/logout") })
+	defer pool.Close()
+	doc := &Document{
+		URL:    ts.URL + "/",
+		Status: 200,
+		Header: http.Header{"Content-Type": []string{"text/html"}},
+		Body:   []byte(`<html><body><script>fetch("/logout")</script></body></html>`),
+	}
+	_, requests, err := pool.Render(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 作用域外的请求仍然会被记录
+	if len(requests) != 1 || requests[0].URL != ts.URL+"/logout" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+	if n := atomic.LoadInt64(&logouts); n != 0 {
+		t.Errorf("out-of-scope request sent %d times", n)
+	}
+}
+
+func TestExplore(t *testing.T) {
+	if _, found := launcher.LookPath(); !found {
+		t.Skip("headless Chrome is not available")
+	}
+	l := launcher.New().Headless(true).MustLaunch()
+	b := rod.New().ControlURL(l).MustConnect()
+	defer b.MustClose()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	pool := NewPool(b, 1, 10*time.Second, nil, nil)
+	defer pool.Close()
+	explorer := NewExplorer(pool, 2, 10)
+	doc := &Document{
+		URL:    ts.URL + "/",
+		Status: 200,
+		Header: http.Header{"Content-Type": []string{"text/html"}},
+		Body: []byte(`<html><body>
+<button onclick="history.pushState({}, '', '/users'); document.body.insertAdjacentHTML('beforeend', '<a onclick=&quot;fetch(\'/api/users/1\')&quot;>detail</a>')">users</button>
+<button onclick="alert('x')">alert</button>
+<button onclick="fetch('/api/delete')">Delete</button>
+</body></html>`),
+	}
+	exploration, err := explorer.Explore(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exploration.Routes) != 1 || exploration.Routes[0] != ts.URL+"/users" {
+		t.Errorf("unexpected routes: %v", exploration.Routes)
+	}
+	var urls []string
+	for _, r := range exploration.Requests {
+		urls = append(urls, r.URL)
+	}
+	if len(urls) != 1 || urls[0] != ts.URL+"/api/users/1" {
+		t.Errorf("unexpected requests: %v", urls)
+	}
+}
+
+func TestExplorerAllowed(t *testing.T) {
+	pool := &Pool{scope: func(link string) bool { return !strings.HasSuffix(link, "/logout") }}
+	explorer := NewExplorer(pool, 2, 10)
+	for target, want := range map[string]bool{
+		"http://example.com/users":  true,
+		"http://example.com/logout": false,
+		"javascript:void(0)":        true,
+		"":                          true,
+	} {
+		if explorer.allowed(&element{Target: target}) != want {
+			t.Errorf("allowed(%q) should be %v", target, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package dedup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+const (
+	ModeNone  = "none"
+	ModeExact = "exact"
+	ModeNear  = "near"
+)
+
+// simhash 指纹分成 4 段建立索引，汉明距离不超过 3 时至少有一段完全相同
+const bands = 4
+
+// Cluster 表示一组被视为重复的响应
+type Cluster struct {
+	URL         string // 第一个出现的响应
+	Fingerprint uint64
+	Count       int // 被合并到该组的响应数量，不包括第一个
+}
+
+// Deduper 根据响应内容的哈希值或 simhash 指纹对响应去重
+type Deduper struct {
+	mutex     sync.Mutex
+	mode      string
+	threshold int
+	normalize bool
+
+	exact    map[uint64]*Cluster
+	clusters []*Cluster
+	index    [bands]map[uint16][]*Cluster
+}
+
+func New(mode string, threshold int, normalize bool) (*Deduper, error) {
+	switch mode {
+	case ModeNone, ModeExact, ModeNear:
+	default:
+		return nil, fmt.Errorf("unknown dedup mode: %s", mode)
+	}
+	if threshold < 0 || threshold > 64 {
+		return nil, fmt.Errorf("invalid simhash threshold: %d", threshold)
+	}
+
+	d := &Deduper{
+		mode:      mode,
+		threshold: threshold,
+		normalize: normalize,
+		exact:     make(map[uint64]*Cluster),
+	}
+	for i := range d.index {
+		d.index[i] = make(map[uint16][]*Cluster)
+	}
+	return d, nil
+}
+
+// IsDuplicate 判断响应是否与之前的响应重复，不重复时记录为新的分组
+func (d *Deduper) IsDuplicate(link string, body []byte) bool {
+	if d.mode == ModeNone {
+		return false
+	}
+	if d.normalize {
+		body = Normalize(link, body)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.mode == ModeExact {
+		h := fnv.New64a()
+		h.Write(body)
+		sum := h.Sum64()
+		if c, ok := d.exact[sum]; ok {
+			c.Count++
+			return true
+		}
+		c := &Cluster{URL: link, Fingerprint: sum}
+		d.exact[sum] = c
+		d.clusters = append(d.clusters, c)
+		return false
+	}
+
+	fp := Simhash(body)
+	if c := d.nearest(fp); c != nil {
+		c.Count++
+		return true
+	}
+	c := &Cluster{URL: link, Fingerprint: fp}
+	d.clusters = append(d.clusters, c)
+	for i := 0; i < bands; i++ {
+		key := band(fp, i)
+		d.index[i][key] = append(d.index[i][key], c)
+	}
+	return false
+}
+
+// nearest 查找汉明距离在阈值以内的分组
+func (d *Deduper) nearest(fp uint64) *Cluster {
+	if d.threshold < bands {
+		for i := 0; i < bands; i++ {
+			for _, c := range d.index[i][band(fp, i)] {
+				if Distance(c.Fingerprint, fp) <= d.threshold {
+					return c
+				}
+			}
+		}
+		return nil
+	}
+	// 阈值较大时分段索引不再可靠，只能逐个比较
+	for _, c := range d.clusters {
+		if Distance(c.Fingerprint, fp) <= d.threshold {
+			return c
+		}
+	}
+	return nil
+}
+
+func band(fp uint64, i int) uint16 {
+	return uint16(fp >> (16 * i))
+}
+
+// Clusters 返回合并了其他响应的分组，按合并数量从多到少排序
+func (d *Deduper) Clusters() []*Cluster {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var result []*Cluster
+	for _, c := range d.clusters {
+		if c.Count > 0 {
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
@@ -0,0 +1,51 @@
+package dedup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExactDedup(t *testing.T) {
+	d, _ := New(ModeExact, 0, false)
+	if d.IsDuplicate("http://example.com/a", []byte(`{"id":1}`)) {
+		t.Error("first response should not be a duplicate")
+	}
+	// 长度相同但内容不同
+	if d.IsDuplicate("http://example.com/b", []byte(`{"id":2}`)) {
+		t.Error("responses with equal length should not be duplicates")
+	}
+	if !d.IsDuplicate("http://example.com/c", []byte(`{"id":1}`)) {
+		t.Error("identical responses should be duplicates")
+	}
+
+	clusters := d.Clusters()
+	if len(clusters) != 1 || clusters[0].URL != "http://example.com/a" || clusters[0].Count != 1 {
+		t.Errorf("wrong clusters: %+v", clusters)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	d, _ := New(ModeExact, 0, true)
+	a := []byte(`<meta name="csrf-token" content="a8f5f167f44f4964"><p>2024-03-01T10:00:00Z /search?q=1</p>`)
+	b := []byte(`<meta name="csrf-token" content="0cc175b9c0f1b6a8"><p>2024-03-02T11:30:12Z /search?q=2</p>`)
+	if d.IsDuplicate("http://example.com/search?q=1", a) {
+		t.Error("first response should not be a duplicate")
+	}
+	if !d.IsDuplicate("http://example.com/search?q=2", b) {
+		t.Error("responses differing only in dynamic tokens should be duplicates")
+	}
+}
+
+func TestNearDedup(t *testing.T) {
+	d, _ := New(ModeNear, 3, false)
+	words := strings.Repeat("lorem ipsum dolor sit amet consectetur adipiscing elit ", 50)
+	if d.IsDuplicate("http://example.com/a", []byte(words+"request 1")) {
+		t.Error("first response should not be a duplicate")
+	}
+	if !d.IsDuplicate("http://example.com/b", []byte(words+"request 2")) {
+		t.Error("nearly identical responses should be duplicates")
+	}
+	if d.IsDuplicate("http://example.com/c", []byte(`{"users":[{"name":"admin","role":"root"}]}`)) {
+		t.Error("different responses should not be duplicates")
+	}
+}
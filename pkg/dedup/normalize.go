@@ -0,0 +1,46 @@
+package dedup
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// 响应中常见的动态内容，去重前替换为固定的占位符
+var dynamicPatterns = []*regexp.Regexp{
+	// ISO 8601 / RFC 3339 时间
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?`),
+	// HTTP 日期，如 Mon, 02 Jan 2006 15:04:05 GMT
+	regexp.MustCompile(`(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} \w{3} \d{4} \d{2}:\d{2}:\d{2} \w+`),
+	// Unix 时间戳（秒或毫秒）
+	regexp.MustCompile(`\b1[0-9]{9}(?:[0-9]{3})?\b`),
+	// CSRF token 和 nonce
+	regexp.MustCompile(`(?i)((?:csrf|xsrf|_token|authenticity_token|nonce)[\w-]*["']?\s*(?:[:=]|content=|value=)\s*["']?)[\w+/=.-]+`),
+	// UUID
+	regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`),
+	// 较长的十六进制串，如请求 ID、哈希值
+	regexp.MustCompile(`(?i)\b[0-9a-f]{32,}\b`),
+}
+
+// Normalize 移除响应中的动态内容（时间、token、反射的请求 URL 等），使内容相同的页面得到相同的指纹
+func Normalize(link string, body []byte) []byte {
+	text := string(body)
+
+	// 页面中反射的请求地址，按从长到短的顺序替换
+	if u, err := url.Parse(link); err == nil && link != "" {
+		for _, reflected := range []string{link, u.RequestURI(), url.QueryEscape(link), u.Path} {
+			if len(reflected) > 3 {
+				text = strings.ReplaceAll(text, reflected, "{url}")
+			}
+		}
+	}
+
+	for _, p := range dynamicPatterns {
+		if p.NumSubexp() > 0 {
+			text = p.ReplaceAllString(text, "${1}{dyn}")
+		} else {
+			text = p.ReplaceAllString(text, "{dyn}")
+		}
+	}
+	return []byte(text)
+}
@@ -0,0 +1,50 @@
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// Simhash 计算文本的 64 位 simhash 指纹，内容相近的文本指纹的汉明距离也较小
+func Simhash(body []byte) uint64 {
+	var weights [64]int
+	tokens := strings.FieldsFunc(string(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	// 使用相邻两个词作为特征，保留一定的顺序信息
+	for i := range tokens {
+		feature := tokens[i]
+		if i+1 < len(tokens) {
+			feature += " " + tokens[i+1]
+		}
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b := 0; b < 64; b++ {
+		if weights[b] > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+	return fingerprint
+}
+
+// Distance 返回两个指纹之间的汉明距离
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
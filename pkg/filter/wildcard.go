@@ -0,0 +1,211 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// WildcardContextKey 是请求上下文中保存字典请求原始地址的键，只有带有该键的响应才会被 WildcardFilter 检查
+const WildcardContextKey = "wildcard_url"
+
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Sample 是一次请求的响应特征
+type Sample struct {
+	Status   int
+	Body     []byte
+	FinalURL string // 重定向之后的地址
+}
+
+// Signature 是通配响应（soft-404）的基线特征
+type Signature struct {
+	Status    int
+	MinLength int
+	MaxLength int
+	MinWords  int
+	MaxWords  int
+	Title     string
+	FinalURL  string // 将请求的文件名替换为 {path} 后的最终地址
+}
+
+func (s *Signature) String() string {
+	return fmt.Sprintf("status=%d length=%d-%d words=%d-%d title=%q location=%s",
+		s.Status, s.MinLength, s.MaxLength, s.MinWords, s.MaxWords, s.Title, s.FinalURL)
+}
+
+// WildcardKey 表示字典请求所在的目录、扩展名以及是否以 / 结尾，相同 key 的请求共享一个基线
+type WildcardKey struct {
+	Dir   string
+	Ext   string
+	Slash bool
+}
+
+func (k WildcardKey) String() string {
+	p := strings.TrimRight(k.Dir, "/") + "/*" + k.Ext
+	if k.Slash {
+		p += "/"
+	}
+	return p
+}
+
+// ParseWildcardKey 解析链接对应的 key 以及其中的文件名（不含扩展名）
+func ParseWildcardKey(link string) (WildcardKey, string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return WildcardKey{}, "", err
+	}
+	trimmed := strings.TrimSuffix(u.Path, "/")
+	base := path.Base(trimmed)
+	ext := path.Ext(base)
+	key := WildcardKey{
+		Dir:   u.Scheme + "://" + u.Host + path.Dir("/"+strings.TrimPrefix(trimmed, "/")),
+		Ext:   ext,
+		Slash: strings.HasSuffix(u.Path, "/") && trimmed != "",
+	}
+	return key, strings.TrimSuffix(base, ext), nil
+}
+
+// ProbeURL 返回 key 下使用随机文件名的探测地址
+func (k WildcardKey) ProbeURL(name string) string {
+	link := strings.TrimRight(k.Dir, "/") + "/" + name + k.Ext
+	if k.Slash {
+		link += "/"
+	}
+	return link
+}
+
+// WildcardFilter 过滤与通配响应基线相同的字典请求结果
+type WildcardFilter struct {
+	mutex      sync.RWMutex
+	signatures map[WildcardKey]*Signature
+}
+
+func NewWildcardFilter() *WildcardFilter {
+	return &WildcardFilter{signatures: make(map[WildcardKey]*Signature)}
+}
+
+// Learned 判断 key 是否已经学习过（即使没有得到基线）
+func (wf *WildcardFilter) Learned(key WildcardKey) bool {
+	wf.mutex.RLock()
+	defer wf.mutex.RUnlock()
+	_, ok := wf.signatures[key]
+	return ok
+}
+
+// Learn 根据随机路径的探测结果学习基线，探测结果不一致时视为没有通配响应并返回 nil
+func (wf *WildcardFilter) Learn(key WildcardKey, names []string, samples []*Sample) *Signature {
+	var sig *Signature
+	for i, s := range samples {
+		body := stripName(s.Body, names[i])
+		length, words := len(body), len(strings.Fields(string(body)))
+		title := extractTitle(body)
+		final := strings.ReplaceAll(s.FinalURL, names[i], "{path}")
+
+		if sig == nil {
+			sig = &Signature{
+				Status:    s.Status,
+				MinLength: length,
+				MaxLength: length,
+				MinWords:  words,
+				MaxWords:  words,
+				Title:     title,
+				FinalURL:  final,
+			}
+			continue
+		}
+		if s.Status != sig.Status || title != sig.Title || final != sig.FinalURL {
+			sig = nil
+			break
+		}
+		sig.MinLength, sig.MaxLength = min(sig.MinLength, length), max(sig.MaxLength, length)
+		sig.MinWords, sig.MaxWords = min(sig.MinWords, words), max(sig.MaxWords, words)
+	}
+
+	wf.mutex.Lock()
+	defer wf.mutex.Unlock()
+	wf.signatures[key] = sig
+	return sig
+}
+
+// Match 判断请求 link 得到的响应是否与基线相同
+func (wf *WildcardFilter) Match(link string, s *Sample) bool {
+	key, name, err := ParseWildcardKey(link)
+	if err != nil {
+		return false
+	}
+	wf.mutex.RLock()
+	sig := wf.signatures[key]
+	wf.mutex.RUnlock()
+	if sig == nil || s.Status != sig.Status {
+		return false
+	}
+
+	body := stripName(s.Body, name)
+	final := s.FinalURL
+	if len(name) >= 3 {
+		final = strings.ReplaceAll(final, name, "{path}")
+	}
+	if final != sig.FinalURL || extractTitle(body) != sig.Title {
+		return false
+	}
+
+	// 允许长度有少量浮动
+	slack := max(16, (sig.MaxLength-sig.MinLength)/2)
+	if l := len(body); l >= sig.MinLength-slack && l <= sig.MaxLength+slack {
+		return true
+	}
+	words := len(strings.Fields(string(body)))
+	return words >= sig.MinWords && words <= sig.MaxWords
+}
+
+func (wf *WildcardFilter) Filter(response *colly.Response) (bool, error) {
+	link := response.Ctx.Get(WildcardContextKey)
+	if link == "" {
+		return false, nil
+	}
+	return wf.Match(link, &Sample{
+		Status:   response.StatusCode,
+		Body:     response.Body,
+		FinalURL: response.Request.URL.String(),
+	}), nil
+}
+
+func (wf *WildcardFilter) Repr() string {
+	wf.mutex.RLock()
+	defer wf.mutex.RUnlock()
+
+	var keys []string
+	for k, sig := range wf.signatures {
+		if sig != nil {
+			keys = append(keys, k.String())
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func (wf *WildcardFilter) ReprVerbose() string {
+	return fmt.Sprintf("Wildcard responses: %s", wf.Repr())
+}
+
+// stripName 移除响应中反射的文件名，避免其长度影响比较
+func stripName(body []byte, name string) []byte {
+	if len(name) < 3 {
+		return body
+	}
+	return []byte(strings.ReplaceAll(string(body), name, ""))
+}
+
+func extractTitle(body []byte) string {
+	if m := titleRegex.FindSubmatch(body); m != nil {
+		return strings.TrimSpace(string(m[1]))
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWildcardFilter(t *testing.T) {
+	page := `<html><title>Not Found</title><p>The page %s does not exist.</p></html>`
+	key, _, _ := ParseWildcardKey("http://example.com/admin/login.php")
+	if key.String() != "http://example.com/admin/*.php" {
+		t.Errorf("wrong key: %s", key)
+	}
+
+	names := []string{"3f1c0a9b7d2e4f60", "a0b1c2d3e4f5a6b7"}
+	var samples []*Sample
+	for _, name := range names {
+		samples = append(samples, &Sample{
+			Status:   200,
+			Body:     []byte(fmt.Sprintf(page, "/admin/"+name+".php")),
+			FinalURL: key.ProbeURL(name),
+		})
+	}
+
+	wf := NewWildcardFilter()
+	if sig := wf.Learn(key, names, samples); sig == nil {
+		t.Fatal("signature should be learned")
+	}
+
+	soft404 := &Sample{
+		Status:   200,
+		Body:     []byte(fmt.Sprintf(page, "/admin/backup.php")),
+		FinalURL: "http://example.com/admin/backup.php",
+	}
+	if !wf.Match("http://example.com/admin/backup.php", soft404) {
+		t.Error("soft 404 response should match the signature")
+	}
+
+	real := &Sample{
+		Status:   200,
+		Body:     []byte(`<html><title>Admin</title><form action="/admin/login.php"></form></html>`),
+		FinalURL: "http://example.com/admin/login.php",
+	}
+	if wf.Match("http://example.com/admin/login.php", real) {
+		t.Error("real response should not match the signature")
+	}
+}
+
+func TestWildcardFilterFailedProbe(t *testing.T) {
+	key, _, _ := ParseWildcardKey("http://example.com/admin/login.php")
+	wf := NewWildcardFilter()
+	// 探测失败时记录空的基线，之后不再探测
+	if sig := wf.Learn(key, nil, nil); sig != nil {
+		t.Errorf("signature should be nil, not %v", sig)
+	}
+	if !wf.Learned(key) {
+		t.Error("key should be learned")
+	}
+	if wf.Match("http://example.com/admin/backup.php", &Sample{Status: 200}) {
+		t.Error("nothing should match an empty signature")
+	}
+}
@@ -0,0 +1,93 @@
+package finder
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// APIDocPaths 是 Swagger/OpenAPI 文档、springfox 分组列表、springdoc 配置和 Swagger UI 配置的常见路径
+var APIDocPaths = []string{
+	"/v2/api-docs",
+	"/v3/api-docs",
+	"/v3/api-docs.yaml",
+	"/api-docs",
+	"/swagger.json",
+	"/swagger.yaml",
+	"/openapi.json",
+	"/openapi.yaml",
+	"/swagger/v1/swagger.json",
+	"/swagger-resources",
+	"/v3/api-docs/swagger-config",
+	"/swagger-ui/swagger-initializer.js",
+}
+
+var (
+	swaggerUIRegex       = regexp.MustCompile(`SwaggerUI(?:Bundle)?\s*\(`)
+	swaggerUIConfigRegex = regexp.MustCompile(`\b(url|configUrl)\s*:\s*["'` + "`" + `]([^"'` + "`" + `\s]+)["'` + "`" + `]`)
+)
+
+// FindLinksFromSwaggerResources 解析 springfox 的 swagger-resources 分组列表，返回每个分组文档的地址
+func FindLinksFromSwaggerResources(data []byte) ([]string, error) {
+	var resources []struct {
+		Name     string `json:"name"`
+		URL      string `json:"url"`
+		Location string `json:"location"`
+	}
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, err
+	}
+	var links []string
+	for _, r := range resources {
+		link := r.URL
+		if link == "" {
+			// 旧版本只有 location 字段
+			link = r.Location
+		}
+		if link != "" && !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// FindLinksFromSwaggerConfig 解析 springdoc 的 swagger-config，返回 url、urls 中的文档地址
+func FindLinksFromSwaggerConfig(data []byte) ([]string, error) {
+	var config struct {
+		URL  string `json:"url"`
+		URLs []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	urls := []string{config.URL}
+	for _, u := range config.URLs {
+		urls = append(urls, u.URL)
+	}
+	var links []string
+	for _, link := range urls {
+		if link = strings.TrimSpace(link); link != "" && !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// FindLinksFromSwaggerUI 获取 Swagger UI 初始化代码（swagger-initializer.js 或页面中的内联脚本）
+// 中 url、urls 和 configUrl 指向的地址。只解析 SwaggerUIBundle 调用之后的代码，避免误把 Swagger UI 库本身的代码当作配置
+func FindLinksFromSwaggerUI(source string) []string {
+	loc := swaggerUIRegex.FindStringIndex(source)
+	if loc == nil {
+		return nil
+	}
+	var links []string
+	for _, m := range swaggerUIConfigRegex.FindAllStringSubmatch(source[loc[1]:], -1) {
+		if !slices.Contains(links, m[2]) {
+			links = append(links, m[2])
+		}
+	}
+	return links
+}
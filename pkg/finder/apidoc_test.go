@@ -0,0 +1,50 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindLinksFromSwaggerResources(t *testing.T) {
+	data := `[{"name":"admin","url":"/v2/api-docs?group=admin","swaggerVersion":"2.0","location":"/v2/api-docs?group=admin"},` +
+		`{"name":"user","location":"/v2/api-docs?group=user","swaggerVersion":"2.0"}]`
+	links, err := FindLinksFromSwaggerResources([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/v2/api-docs?group=admin", "/v2/api-docs?group=user"}; !slices.Equal(links, want) {
+		t.Errorf("links should be %v, not %v", want, links)
+	}
+	if _, err := FindLinksFromSwaggerResources([]byte("<html></html>")); err == nil {
+		t.Error("should fail to parse html")
+	}
+}
+
+func TestFindLinksFromSwaggerConfig(t *testing.T) {
+	data := `{"configUrl":"/v3/api-docs/swagger-config","url":"/v3/api-docs","urls":[{"url":"/v3/api-docs/public","name":"public"},{"url":"/v3/api-docs","name":"all"}]}`
+	links, err := FindLinksFromSwaggerConfig([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/v3/api-docs", "/v3/api-docs/public"}; !slices.Equal(links, want) {
+		t.Errorf("links should be %v, not %v", want, links)
+	}
+}
+
+func TestFindLinksFromSwaggerUI(t *testing.T) {
+	source := `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "https://petstore.swagger.io/v2/swagger.json",
+    urls: [{url: './api/openapi.yaml', name: 'api'}],
+    dom_id: '#swagger-ui',
+    configUrl: "/swagger-config.json",
+  });
+};`
+	want := []string{"https://petstore.swagger.io/v2/swagger.json", "./api/openapi.yaml", "/swagger-config.json"}
+	if links := FindLinksFromSwaggerUI(source); !slices.Equal(links, want) {
+		t.Errorf("links should be %v, not %v", want, links)
+	}
+	if links := FindLinksFromSwaggerUI(`var o={url:"/not/config"}`); links != nil {
+		t.Errorf("links should be empty without SwaggerUIBundle, not %v", links)
+	}
+}
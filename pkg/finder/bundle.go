@@ -0,0 +1,223 @@
+package finder
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// 动态加载资源的打包工具
+const (
+	ChunkWebpack   = "webpack"
+	ChunkVite      = "vite"
+	ChunkImport    = "import"
+	ChunkImportMap = "importmap"
+)
+
+var (
+	// webpack 4/5 的 publicPath，如 r.p="/static/"
+	publicPathRegex = regexp.MustCompile(`\b[\w$]+\.p\s*=\s*["']([^"']*)["']`)
+	// webpack 5 中返回 chunk 文件名的函数，如 r.u=e=>"js/"+e+"."+{...}[e]+".js"
+	chunkFuncRegex = regexp.MustCompile(`\b[\w$]+\.(?:u|miniCssF|k)\s*=\s*`)
+	// 以 publicPath 开头的 URL 表达式，如 o.p+"chunks/"+({1:"todo"}[e]||e)+".js"
+	publicPathExprRegex = regexp.MustCompile(`\b[\w$]+(?:\.[\w$]+)*\.p\s*\+`)
+	// webpack 4 中加载 CSS chunk 的表达式，如 var href="css/"+({...}[e]||e)+".css"
+	hrefExprRegex = regexp.MustCompile(`\b(?:href|src)\s*=\s*["'(]`)
+	// 动态 import()
+	dynamicImportRegex = regexp.MustCompile(`\bimport\(\s*(?:"([^"]+)"|'([^']+)'|` + "`([^`$]+)`" + `)\s*\)`)
+	// Vite 预加载的依赖列表：__vitePreload(() => import("..."), [...]) 和 __vite__mapDeps 中的 m.f=[...]
+	vitePreloadRegex = regexp.MustCompile(`\bimport\([^()]*\)\s*,\s*\[|\b[\w$]+\.f\s*=\s*\[`)
+	// 函数参数，如 function(e){return 或 e=>
+	funcHeaderRegex = regexp.MustCompile(`^(?:function\s*[\w$]*\s*\(\s*([\w$]+)\s*\)\s*\{\s*return\b\s*|\(?\s*([\w$]+)\s*\)?\s*=>\s*(?:\{\s*return\b\s*)?)`)
+	// 对象字面量的属性名
+	objectKeyRegex = regexp.MustCompile(`(?:^|[{,])\s*(?:"([^"]*)"|'([^']*)'|([\w$]+))\s*:`)
+	// 对象查找表达式 {...}[e]
+	lookupRegex      = regexp.MustCompile(`\{([^{}]*)\}\s*\[\s*[\w$]+\s*\]`)
+	lookupParamRegex = regexp.MustCompile(`\}\s*\[\s*([\w$]+)\s*\]`)
+)
+
+// Chunk 是从打包代码中解析出的动态加载资源
+type Chunk struct {
+	Path     string
+	Kind     string
+	Relative bool // 路径相对于当前 JS 文件，而不是网站根路径
+}
+
+// FindChunksFromJS 静态分析 webpack、Vite、Rollup 打包的代码，获取动态加载的 chunk 路径，
+// 无法静态计算的表达式在沙箱中执行
+func FindChunksFromJS(source string) []*Chunk {
+	var chunks []*Chunk
+	add := func(c *Chunk) {
+		if c.Path == "" || strings.ContainsAny(c.Path, " \n<>") {
+			return
+		}
+		if !slices.ContainsFunc(chunks, func(o *Chunk) bool { return o.Path == c.Path }) {
+			chunks = append(chunks, c)
+		}
+	}
+
+	publicPath, relative := "", false
+	if m := publicPathRegex.FindStringSubmatch(source); m != nil {
+		if m[1] == "auto" {
+			// webpack 5 根据当前脚本的地址计算 publicPath
+			relative = true
+		} else {
+			publicPath = m[1]
+		}
+	}
+	for _, p := range findWebpackChunks(source) {
+		add(&Chunk{Path: publicPath + p, Kind: ChunkWebpack, Relative: relative && !strings.HasPrefix(p, "/")})
+	}
+
+	for _, m := range vitePreloadRegex.FindAllStringIndex(source, -1) {
+		for _, p := range parseStringArray(source[m[1]-1:]) {
+			add(&Chunk{Path: p, Kind: ChunkVite})
+		}
+	}
+	for _, m := range dynamicImportRegex.FindAllStringSubmatch(source, -1) {
+		p := m[1] + m[2] + m[3]
+		if strings.HasPrefix(p, ".") || strings.HasPrefix(p, "/") || strings.Contains(p, "://") {
+			add(&Chunk{Path: p, Kind: ChunkImport, Relative: strings.HasPrefix(p, ".")})
+		}
+	}
+	return chunks
+}
+
+// FindLinksFromImportMap 获取 <script type="importmap"> 中的模块地址
+func FindLinksFromImportMap(source string) []string {
+	var importMap struct {
+		Imports map[string]string            `json:"imports"`
+		Scopes  map[string]map[string]string `json:"scopes"`
+	}
+	if err := json.Unmarshal([]byte(source), &importMap); err != nil {
+		return nil
+	}
+	var links []string
+	for _, link := range importMap.Imports {
+		links = append(links, link)
+	}
+	for _, scope := range importMap.Scopes {
+		for _, link := range scope {
+			links = append(links, link)
+		}
+	}
+	slices.Sort(links)
+	return slices.Compact(links)
+}
+
+// findWebpackChunks 计算 webpack chunk 文件名表达式在各个 chunk id 下的值
+func findWebpackChunks(source string) []string {
+	var paths []string
+	// webpack 5：__webpack_require__.u 和 miniCssF 函数
+	for _, m := range chunkFuncRegex.FindAllStringIndex(source, -1) {
+		fn := extractExpression(source[m[1]:])
+		header := funcHeaderRegex.FindStringSubmatch(fn)
+		if header == nil {
+			continue
+		}
+		param := header[1] + header[2]
+		body := strings.TrimRight(strings.TrimSpace(fn[len(header[0]):]), ";}")
+		results, err := evalChunkExpression(body, param)
+		if err != nil {
+			results = evalChunkFunction(fn)
+		}
+		paths = append(paths, results...)
+	}
+	// webpack 4：以 publicPath 开头的表达式
+	for _, m := range publicPathExprRegex.FindAllStringIndex(source, -1) {
+		expr := extractExpression(source[m[0]:])
+		if !strings.Contains(expr, `"`) && !strings.Contains(expr, `'`) {
+			continue
+		}
+		param := lookupParam(expr)
+		results, err := evalChunkExpression(expr, param)
+		if err != nil && param != "" {
+			results = evalChunkFunction("function(" + param + "){return " + publicPathObject(expr) + expr + "}")
+		}
+		paths = append(paths, results...)
+	}
+	// webpack 4：CSS chunk
+	for _, m := range hrefExprRegex.FindAllStringIndex(source, -1) {
+		expr := extractExpression(source[m[1]-1:])
+		if !lookupRegex.MatchString(expr) {
+			continue
+		}
+		if results, err := evalChunkExpression(expr, lookupParam(expr)); err == nil {
+			paths = append(paths, results...)
+		}
+	}
+	return paths
+}
+
+// lookupParam 返回表达式中对象查找使用的变量名，即 chunk id
+func lookupParam(expr string) string {
+	m := lookupParamRegex.FindStringSubmatch(expr)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// publicPathObject 为表达式中的 publicPath 对象生成定义，使其可以在沙箱中执行
+func publicPathObject(expr string) string {
+	m := publicPathExprRegex.FindString(expr)
+	name, _, _ := strings.Cut(m, ".")
+	return "var " + name + "={p:\"\"};"
+}
+
+// extractExpression 从 source 开头截取一个完整的表达式，直到顶层的 , ; 或不匹配的右括号
+func extractExpression(source string) string {
+	depth := 0
+	for i := 0; i < len(source); i++ {
+		switch c := source[i]; c {
+		case '"', '\'', '`':
+			for i++; i < len(source) && source[i] != c; i++ {
+				if source[i] == '\\' {
+					i++
+				}
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return source[:i]
+			}
+			depth--
+		case ',', ';', '\n':
+			if depth == 0 {
+				return source[:i]
+			}
+		}
+		// 避免在压缩代码中截取过长的内容
+		if i > 100000 {
+			return ""
+		}
+	}
+	return source
+}
+
+// parseStringArray 解析以 [ 开头的字符串数组
+func parseStringArray(source string) []string {
+	expr := extractExpression(source)
+	var items []string
+	if err := json.Unmarshal([]byte(strings.ReplaceAll(expr, "'", `"`)), &items); err != nil {
+		return nil
+	}
+	return items
+}
+
+// objectKeys 返回表达式中所有对象查找表的属性名，作为候选的 chunk id
+func objectKeys(expr string) []string {
+	var keys []string
+	for _, lookup := range lookupRegex.FindAllStringSubmatch(expr, -1) {
+		for _, m := range objectKeyRegex.FindAllStringSubmatch("{"+lookup[1], -1) {
+			keys = append(keys, m[1]+m[2]+m[3])
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+var errUnsupported = errors.New("unsupported expression")
@@ -0,0 +1,349 @@
+package finder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dop251/goja"
+)
+
+// 沙箱中执行单个表达式的超时时间
+const evalTimeout = 100 * time.Millisecond
+
+// chunkNode 是 chunk 文件名表达式的语法树节点，eval 返回表达式在给定 chunk id 下的值
+type chunkNode interface {
+	eval(id string) (string, bool)
+}
+
+type (
+	literal    string
+	chunkParam struct{}
+	concat     []chunkNode
+	// alternative 是 a||b，a 没有值时使用 b
+	alternative struct{ a, b chunkNode }
+	// lookup 是 {...}[e]
+	lookup struct {
+		table map[string]string
+	}
+)
+
+func (n literal) eval(string) (string, bool) { return string(n), true }
+
+func (chunkParam) eval(id string) (string, bool) { return id, true }
+
+func (n concat) eval(id string) (string, bool) {
+	var sb strings.Builder
+	for _, c := range n {
+		v, ok := c.eval(id)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(v)
+	}
+	return sb.String(), true
+}
+
+func (n alternative) eval(id string) (string, bool) {
+	if v, ok := n.a.eval(id); ok && v != "" {
+		return v, true
+	}
+	return n.b.eval(id)
+}
+
+func (n lookup) eval(id string) (string, bool) {
+	v, ok := n.table[id]
+	return v, ok
+}
+
+// evalChunkExpression 静态计算 chunk 文件名表达式，表达式只能由字符串、chunk id、publicPath
+// 和对象查找组成，其他表达式返回 errUnsupported
+func evalChunkExpression(expr, param string) ([]string, error) {
+	p := &exprParser{tokens: tokenize(expr), param: param}
+	root, err := p.parseConcat()
+	if err != nil || p.pos != len(p.tokens) {
+		return nil, errUnsupported
+	}
+
+	var ids []string
+	var required, optional []string
+	walkLookups(root, false, func(l lookup, fallback bool) {
+		for k := range l.table {
+			if fallback {
+				optional = append(optional, k)
+			} else {
+				required = append(required, k)
+			}
+		}
+	})
+	// 没有默认值的查找表包含了所有 chunk id
+	if len(required) > 0 {
+		ids = required
+	} else {
+		ids = optional
+	}
+	if len(ids) == 0 {
+		if containsParam(root) {
+			return nil, errUnsupported
+		}
+		ids = []string{""}
+	}
+
+	var results []string
+	for _, id := range ids {
+		if v, ok := root.eval(id); ok && v != "" {
+			results = append(results, v)
+		}
+	}
+	return results, nil
+}
+
+// evalChunkFunction 在沙箱中执行返回 chunk 文件名的函数，依次传入函数中对象查找表的所有属性名
+func evalChunkFunction(fn string) []string {
+	ids := objectKeys(fn)
+	if len(ids) == 0 {
+		return nil
+	}
+	vm := goja.New()
+	timer := time.AfterFunc(evalTimeout*time.Duration(len(ids)), func() {
+		vm.Interrupt("timeout")
+	})
+	defer timer.Stop()
+
+	f, err := vm.RunString("(" + fn + ")")
+	if err != nil {
+		return nil
+	}
+	call, ok := goja.AssertFunction(f)
+	if !ok {
+		return nil
+	}
+	var results []string
+	for _, id := range ids {
+		var arg goja.Value = vm.ToValue(id)
+		// 数字 id 需要以数字传入，否则 {1:"a"}[e] 之外的运算（如 e+1）结果不同
+		if n, err := strconv.Atoi(id); err == nil {
+			arg = vm.ToValue(n)
+		}
+		v, err := call(goja.Undefined(), arg)
+		if err != nil {
+			continue
+		}
+		if s, ok := v.Export().(string); ok && s != "" && !strings.Contains(s, "undefined") {
+			results = append(results, s)
+		}
+	}
+	return results
+}
+
+func walkLookups(n chunkNode, fallback bool, fn func(lookup, bool)) {
+	switch n := n.(type) {
+	case concat:
+		for _, c := range n {
+			walkLookups(c, fallback, fn)
+		}
+	case alternative:
+		walkLookups(n.a, true, fn)
+		walkLookups(n.b, fallback, fn)
+	case lookup:
+		fn(n, fallback)
+	}
+}
+
+func containsParam(n chunkNode) bool {
+	switch n := n.(type) {
+	case chunkParam:
+		return true
+	case concat:
+		for _, c := range n {
+			if containsParam(c) {
+				return true
+			}
+		}
+	case alternative:
+		return containsParam(n.a) || containsParam(n.b)
+	}
+	return false
+}
+
+type token struct {
+	kind  byte // s: 字符串, n: 数字, i: 标识符, 其他为标点
+	value string
+}
+
+// tokenize 将表达式拆分为 token，遇到无法识别的字符时返回 nil
+func tokenize(expr string) []token {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(expr) && expr[j] != c; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				} else if c == '`' && expr[j] == '$' && j+1 < len(expr) && expr[j+1] == '{' {
+					// 含有插值的模板字符串无法静态计算
+					return nil
+				}
+				sb.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil
+			}
+			tokens = append(tokens, token{'s', sb.String()})
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.' || expr[j] == 'e') {
+				j++
+			}
+			tokens = append(tokens, token{'n', expr[i:j]})
+			i = j
+		case c == '_' || c == '$' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '$' || expr[j] == '.' ||
+				unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{'i', expr[i:j]})
+			i = j
+		case c == '|' && i+1 < len(expr) && expr[i+1] == '|':
+			tokens = append(tokens, token{'|', "||"})
+			i += 2
+		case strings.IndexByte("+()[]{}:,", c) >= 0:
+			tokens = append(tokens, token{c, string(c)})
+			i++
+		default:
+			return nil
+		}
+	}
+	return tokens
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+	param  string
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos >= len(p.tokens) {
+		return 0
+	}
+	return p.tokens[p.pos].kind
+}
+
+func (p *exprParser) expect(kind byte) (token, error) {
+	if p.peek() != kind {
+		return token{}, errUnsupported
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *exprParser) parseConcat() (chunkNode, error) {
+	var nodes concat
+	for {
+		n, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+		if p.peek() != '+' {
+			break
+		}
+		p.pos++
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *exprParser) parseTerm() (chunkNode, error) {
+	switch p.peek() {
+	case 's':
+		// 数字参与的 + 可能是加法，交给沙箱执行
+		t := p.tokens[p.pos]
+		p.pos++
+		return literal(t.value), nil
+	case 'i':
+		t := p.tokens[p.pos]
+		p.pos++
+		switch {
+		case t.value == p.param:
+			return chunkParam{}, nil
+		case strings.HasSuffix(t.value, ".p"):
+			// publicPath 由调用方处理
+			return literal(""), nil
+		}
+		return nil, errUnsupported
+	case '{':
+		table, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect('['); err != nil {
+			return nil, err
+		}
+		if t, err := p.expect('i'); err != nil || t.value != p.param {
+			return nil, errUnsupported
+		}
+		if _, err := p.expect(']'); err != nil {
+			return nil, err
+		}
+		return lookup{table: table}, nil
+	case '(':
+		p.pos++
+		a, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() == '|' {
+			p.pos++
+			b, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			a = alternative{a, b}
+		}
+		if _, err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	return nil, errUnsupported
+}
+
+func (p *exprParser) parseObject() (map[string]string, error) {
+	table := make(map[string]string)
+	p.pos++
+	for p.peek() != '}' {
+		if k := p.peek(); k != 's' && k != 'n' && k != 'i' {
+			return nil, errUnsupported
+		}
+		key := p.tokens[p.pos]
+		p.pos++
+		if _, err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.expect('s')
+		if err != nil {
+			return nil, err
+		}
+		table[key.value] = value.value
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != '}' {
+			return nil, errUnsupported
+		}
+	}
+	p.pos++
+	return table, nil
+}
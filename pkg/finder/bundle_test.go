@@ -0,0 +1,63 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+func chunkPaths(chunks []*Chunk) []string {
+	var paths []string
+	for _, c := range chunks {
+		paths = append(paths, c.Path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestFindChunksFromJS(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "webpack 4",
+			source: `!function(e){function n(n){for(var t,o,i=n[0],c=n[1],u=0,a=[];u<i.length;u++)o=i[u],r[o]&&a.push(r[o][0]),r[o]=0;for(t in c)Object.prototype.hasOwnProperty.call(c,t)&&(e[t]=c[t]);for(s&&s(n);a.length;)a.shift()()}var t={},r={0:0};function o(n){if(t[n])return t[n].exports;var r=t[n]={i:n,l:!1,exports:{}};return e[n].call(r.exports,r,r.exports,o),r.l=!0,r.exports}o.e=function(e){var n=[],t=r[e];if(0!==t)if(t)n.push(t[2]);else{var i=new Promise(function(n,o){t=r[e]=[n,o]});n.push(t[2]=i);var c,u=document.createElement("script");u.charset="utf-8",u.timeout=120,o.nc&&u.setAttribute("nonce",o.nc),u.src=function(e){return o.p+"chunks/"+({1:"todo"}[e]||e)+"."+{1:"d41d8cd98f00b204e980"}[e]+".js"}(e),c=function(n){u.onerror=u.onload=null,clearTimeout(s);var t=r[e];if(0!==t){if(t){var o=n&&("load"===n.type?"missing":n.type),i=n&&n.target&&n.target.src,c=new Error("Loading chunk "+e+" failed.\n("+o+": "+i+")");c.type=o,c.request=i,t[1](c)}r[e]=void 0}};var s=setTimeout(function(){c({type:"timeout",target:u})},12e4);u.onerror=u.onload=c,document.head.appendChild(u)}return Promise.all(n)},o.m=e,o.c=t,o.d=function(e,n,t){o.o(e,n)||Object.defineProperty(e,n,{enumerable:!0,get:t})},o.r=function(e){"undefined"!=typeof Symbol&&Symbol.toStringTag&&Object.defineProperty(e,Symbol.toStringTag,{value:"Module"}),Object.defineProperty(e,"__esModule",{value:!0})},o.t=function(e,n){if(1&n&&(e=o(e)),8&n)return e;if(4&n&&"object"==typeof e&&e&&e.__esModule)return e;var t=Object.create(null);if(o.r(t),Object.defineProperty(t,"default",{enumerable:!0,value:e}),2&n&&"string"!=typeof e)for(var r in e)o.d(t,r,function(n){return e[n]}.bind(null,r));return t},o.n=function(e){var n=e&&e.__esModule?function(){return e.default}:function(){return e};return o.d(n,"a",n),n},o.o=function(e,n){return Object.prototype.hasOwnProperty.call(e,n)},o.p="",o.oe=function(e){throw console.error(e),e};var i=window.webpackJsonp=window.webpackJsonp||[],c=i.push.bind(i);i.push=n,i=i.slice();for(var u=0;u<i.length;u++)n(i[u]);var s=c;o(o.s=0)}([function(e,n,t){"use strict";t.r(n);var r={title:"Main Application"},o={init:function(){this.appElement=document.querySelector("#app"),this.initEvents(),this.render()},initEvents:function(){var e=this;this.appElement.addEventListener("click",function(e){"btn-todo"===e.target.className&&t.e(1).then(t.bind(null,1)).then(function(e){e.TodoModule.init()}).catch(function(e){return"An error occurred while loading Module"})}),document.querySelector(".banner").addEventListener("click",function(n){n.preventDefault(),e.render()})},render:function(){this.appElement.innerHTML='\n    <section class="app">\n        <h3> '.concat(r.title,' </h3>\n        <section class="button">\n            <button class="btn-todo"> Todo Module </button>\n        </section>\n    </section>\n')}};({init:function(){this.initComponents(),this.initServiceWorker()},initComponents:function(){o.init()},initServiceWorker:function(){navigator.serviceWorker&&navigator.serviceWorker.register("./sw.js").then(function(){console.log("sw registered successfully!")}).catch(function(e){console.log("Some error while registering sw:",e)})}}).init()}]);`,
+			want:   []string{"chunks/todo.d41d8cd98f00b204e980.js"},
+		},
+		{
+			name: "webpack 5",
+			source: `r.p="/static/",r.u=e=>"js/"+({96:"about"}[e]||e)+"."+{96:"a1b2",179:"c3d4"}[e]+".chunk.js",` +
+				`r.miniCssF=function(e){return"css/"+e+"."+{96:"e5f6"}[e]+".css"};`,
+			want: []string{"/static/css/96.e5f6.css", "/static/js/179.c3d4.chunk.js", "/static/js/about.a1b2.chunk.js"},
+		},
+		{
+			name:   "sandbox",
+			source: `r.u=function(e){return"js/"+(e+1)+"."+{1:"abc"}[e]+".js"};`,
+			want:   []string{"js/2.abc.js"},
+		},
+		{
+			name:   "vite",
+			source: `const m=__vitePreload(()=>import("./About-1a2b.js"),["assets/About-1a2b.js","assets/About-3c4d.css"]);`,
+			want:   []string{"./About-1a2b.js", "assets/About-1a2b.js", "assets/About-3c4d.css"},
+		},
+		{
+			name:   "dynamic import",
+			source: `const a=()=>import('./pages/admin.js'),b=()=>import("lodash");`,
+			want:   []string{"./pages/admin.js"},
+		},
+	}
+	for _, tt := range tests {
+		if got := chunkPaths(FindChunksFromJS(tt.source)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindLinksFromImportMap(t *testing.T) {
+	source := `{"imports":{"vue":"/assets/vue.js","app/":"/assets/app/"},"scopes":{"/admin/":{"vue":"/assets/vue.admin.js"}}}`
+	want := []string{"/assets/app/", "/assets/vue.admin.js", "/assets/vue.js"}
+	if got := FindLinksFromImportMap(source); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,207 @@
+package finder
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 表单的编码类型
+const (
+	EnctypeURLEncoded = "application/x-www-form-urlencoded"
+	EnctypeMultipart  = "multipart/form-data"
+	EnctypeText       = "text/plain"
+)
+
+// 常见的 CSRF token 字段名
+var csrfNameRegex = regexp.MustCompile(`(?i)csrf|xsrf|authenticity_token|requestverificationtoken|^_?token$|nonce`)
+
+// FormValues 是自动填写表单时各类型输入框使用的值，提交表单和浏览器中的页面交互共用
+var FormValues = map[string]string{
+	"text":           "test",
+	"search":         "test",
+	"email":          "gatherer@1234.com",
+	"password":       "Test@1234",
+	"number":         "1",
+	"range":          "1",
+	"tel":            "13800000000",
+	"url":            "http://example.com",
+	"date":           "1985-04-12",
+	"datetime-local": "1985-04-12T23:20",
+	"time":           "23:20",
+	"month":          "1985-04",
+	"week":           "1985-W15",
+	"color":          "#000000",
+	"textarea":       "test",
+}
+
+// Form 是从 HTML 中解析出的表单
+type Form struct {
+	Method  string       // 大写的请求方法，默认为 GET
+	Action  string       // action 属性的原始值，为空时表单提交到当前页面
+	Enctype string       // 请求体的编码类型
+	Fields  []*FormField // 表单中所有有 name 的字段
+}
+
+// FormField 是表单中的一个字段
+type FormField struct {
+	Name     string
+	Type     string   // input 的 type，以及 select、textarea、button
+	Value    string   // 页面中的默认值
+	Options  []string // select、radio 的可选值
+	Required bool
+	Checked  bool // checkbox、radio 是否默认选中
+	CSRF     bool // 可能是 CSRF token 的隐藏字段
+}
+
+// ParseForm 解析 form 元素，disabled 的字段不会随表单提交，因此忽略
+func ParseForm(s *goquery.Selection) *Form {
+	form := &Form{
+		Method:  strings.ToUpper(strings.TrimSpace(s.AttrOr("method", ""))),
+		Action:  strings.TrimSpace(s.AttrOr("action", "")),
+		Enctype: strings.ToLower(strings.TrimSpace(s.AttrOr("enctype", ""))),
+	}
+	// 表单的 method 只能是 GET、POST 和 dialog
+	if form.Method != "POST" {
+		form.Method = "GET"
+	}
+	if form.Enctype != EnctypeMultipart && form.Enctype != EnctypeText {
+		form.Enctype = EnctypeURLEncoded
+	}
+
+	s.Find("input[name], select[name], textarea[name], button[name]").Each(func(_ int, el *goquery.Selection) {
+		if _, disabled := el.Attr("disabled"); disabled {
+			return
+		}
+		name := el.AttrOr("name", "")
+		_, required := el.Attr("required")
+		field := &FormField{Name: name, Required: required}
+
+		switch tag := goquery.NodeName(el); tag {
+		case "select":
+			field.Type = tag
+			el.Find("option").Each(func(_ int, option *goquery.Selection) {
+				value := option.AttrOr("value", strings.TrimSpace(option.Text()))
+				field.Options = append(field.Options, value)
+				if _, selected := option.Attr("selected"); selected || field.Value == "" && len(field.Options) == 1 {
+					field.Value = value
+				}
+			})
+		case "textarea":
+			field.Type = tag
+			field.Value = el.Text()
+		case "button":
+			field.Type = strings.ToLower(el.AttrOr("type", "submit"))
+			field.Value = el.AttrOr("value", "")
+		default:
+			field.Type = strings.ToLower(el.AttrOr("type", "text"))
+			field.Value = el.AttrOr("value", "")
+			_, field.Checked = el.Attr("checked")
+			if field.Type == "checkbox" || field.Type == "radio" {
+				if field.Value == "" {
+					field.Value = "on"
+				}
+				// 同名的 radio 合并为一个字段
+				i := slices.IndexFunc(form.Fields, func(f *FormField) bool { return f.Name == name && f.Type == field.Type })
+				if i >= 0 && field.Type == "radio" {
+					prev := form.Fields[i]
+					prev.Options = append(prev.Options, field.Value)
+					if field.Checked && !prev.Checked {
+						prev.Value, prev.Checked = field.Value, true
+					}
+					return
+				}
+				field.Options = []string{field.Value}
+			}
+		}
+		field.CSRF = field.Type == "hidden" && field.Value != "" && csrfNameRegex.MatchString(name)
+		form.Fields = append(form.Fields, field)
+	})
+	return form
+}
+
+// Names 返回所有字段名
+func (f *Form) Names() []string {
+	var names []string
+	for _, field := range f.Fields {
+		if !slices.Contains(names, field.Name) {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+// HasPassword 判断表单中是否有密码框
+func (f *Form) HasPassword() bool {
+	return slices.ContainsFunc(f.Fields, func(field *FormField) bool { return field.Type == "password" })
+}
+
+// Values 返回提交表单时各字段的值：有默认值时使用默认值，否则按字段类型生成；
+// 未选中的 checkbox 和 radio 会被选中，只提交第一个提交按钮
+func (f *Form) Values() url.Values {
+	values := url.Values{}
+	submitted := false
+	for _, field := range f.Fields {
+		switch field.Type {
+		case "submit", "image":
+			if !submitted {
+				values.Add(field.Name, field.Value)
+				submitted = true
+			}
+		case "button", "reset":
+		case "file":
+			values.Add(field.Name, "")
+		case "select", "hidden", "checkbox", "radio":
+			values.Add(field.Name, field.Value)
+		default:
+			value := field.Value
+			if value == "" {
+				value = FormValues[field.Type]
+				if value == "" {
+					value = FormValues["text"]
+				}
+			}
+			values.Add(field.Name, value)
+		}
+	}
+	return values
+}
+
+// Encode 按表单的编码类型生成请求体和 Content-Type，GET 表单的值应该放在查询字符串中
+func (f *Form) Encode() ([]byte, string) {
+	values := f.Values()
+	switch f.Enctype {
+	case EnctypeMultipart:
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for _, field := range f.Fields {
+			if field.Type == "file" {
+				w.CreateFormFile(field.Name, "test.txt")
+			}
+		}
+		for name, vs := range values {
+			if slices.ContainsFunc(f.Fields, func(field *FormField) bool { return field.Name == name && field.Type == "file" }) {
+				continue
+			}
+			for _, v := range vs {
+				w.WriteField(name, v)
+			}
+		}
+		w.Close()
+		return buf.Bytes(), w.FormDataContentType()
+	case EnctypeText:
+		var sb strings.Builder
+		for name, vs := range values {
+			for _, v := range vs {
+				sb.WriteString(name + "=" + v + "\r\n")
+			}
+		}
+		return []byte(sb.String()), EnctypeText
+	}
+	return []byte(values.Encode()), EnctypeURLEncoded
+}
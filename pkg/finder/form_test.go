@@ -0,0 +1,72 @@
+package finder
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseForm(t *testing.T) {
+	html := `<form action="/profile" method="post" enctype="multipart/form-data">
+	<input type="hidden" name="csrf_token" value="abc123">
+	<input name="nickname" required>
+	<input type="email" name="email">
+	<input type="radio" name="gender" value="m">
+	<input type="radio" name="gender" value="f" checked>
+	<input type="checkbox" name="subscribe">
+	<input name="disabled" disabled>
+	<select name="city"><option value="bj">Beijing</option><option value="sh" selected>Shanghai</option></select>
+	<textarea name="bio">hi</textarea>
+	<input type="file" name="avatar">
+	<button type="submit" name="action" value="save">Save</button>
+	<button type="submit" name="action2" value="cancel">Cancel</button>
+</form>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := ParseForm(doc.Find("form"))
+
+	if form.Method != "POST" || form.Action != "/profile" || form.Enctype != EnctypeMultipart {
+		t.Errorf("wrong form: %+v", form)
+	}
+	want := []string{"csrf_token", "nickname", "email", "gender", "subscribe", "city", "bio", "avatar", "action", "action2"}
+	names := form.Names()
+	if !slices.Equal(names, want) {
+		t.Fatalf("wrong fields: %v", names)
+	}
+	if !form.Fields[0].CSRF || form.Fields[1].CSRF || !form.Fields[1].Required {
+		t.Error("wrong csrf or required flag")
+	}
+	if gender := form.Fields[3]; gender.Value != "f" || !slices.Equal(gender.Options, []string{"m", "f"}) {
+		t.Errorf("wrong radio field: %+v", gender)
+	}
+
+	values := form.Values()
+	expected := map[string]string{
+		"csrf_token": "abc123",
+		"nickname":   "test",
+		"email":      "gatherer@1234.com",
+		"gender":     "f",
+		"subscribe":  "on",
+		"city":       "sh",
+		"bio":        "hi",
+		"action":     "save",
+	}
+	for name, value := range expected {
+		if values.Get(name) != value {
+			t.Errorf("value of %s should be %q, not %q", name, value, values.Get(name))
+		}
+	}
+	if values.Has("action2") {
+		t.Error("only the first submit button should be submitted")
+	}
+
+	body, contentType := form.Encode()
+	if !strings.HasPrefix(contentType, "multipart/form-data; boundary=") ||
+		!strings.Contains(string(body), `name="avatar"; filename="test.txt"`) {
+		t.Errorf("wrong multipart body: %s", body)
+	}
+}
@@ -0,0 +1,353 @@
+package finder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// GraphQL 操作的类型
+const (
+	GraphQLQuery        = "query"
+	GraphQLMutation     = "mutation"
+	GraphQLSubscription = "subscription"
+)
+
+// 生成查询时嵌套输入对象和选择字段的最大数量
+const (
+	maxInputDepth    = 3
+	maxSelectedField = 20
+)
+
+// GraphQLPaths 是 GraphQL 接口常见的路径
+var GraphQLPaths = []string{
+	"/graphql",
+	"/api/graphql",
+	"/graphql/v1",
+	"/v1/graphql",
+	"/v2/graphql",
+	"/gql",
+	"/query",
+	"/graphql.php",
+}
+
+// IntrospectionQuery 查询 schema 中的所有类型及其字段、参数和枚举值
+const IntrospectionQuery = `query IntrospectionQuery { __schema { queryType { name } mutationType { name } subscriptionType { name } ` +
+	`types { kind name fields(includeDeprecated: true) { name args { name type { ...TypeRef } } type { ...TypeRef } } ` +
+	`inputFields { name type { ...TypeRef } } enumValues(includeDeprecated: true) { name } } } } ` +
+	`fragment TypeRef on __Type { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } }`
+
+var (
+	graphqlEndpointRegex  = regexp.MustCompile("[\"'`]((?:https?://[\\w.:-]+)?(?:/[\\w.-]+)*/(?:graphql|gql)(?:/[\\w.-]+)*/?)[\"'`]")
+	graphqlOperationRegex = regexp.MustCompile(`\b(query|mutation|subscription)\s+([A-Za-z_]\w*)\s*[({]`)
+	graphqlFragmentRegex  = regexp.MustCompile(`\bfragment\s+([A-Za-z_]\w*)\s+on\s+[A-Za-z_]\w*\s*\{`)
+	graphqlSpreadRegex    = regexp.MustCompile(`\.\.\.\s*([A-Za-z_]\w*)`)
+	// Apollo、Relay 等工具编译后的文档
+	graphqlASTRegex = regexp.MustCompile(`operation:\s*"(query|mutation|subscription)",\s*name:\s*\{\s*kind:\s*"Name",\s*value:\s*"(\w+)"`)
+	// graphql-js、Apollo Server 等实现特有的错误信息，普通 REST 接口的 "syntax error" 等信息不算
+	graphqlErrorRegex = regexp.MustCompile(`(?i)graphql|must provide (?:a )?query|query (?:string )?(?:is )?missing|syntax error: (?:unexpected|expected)|cannot query field|unknown (?:type|argument) "|unknown operation named|introspection (?:is )?(?:disabled|not allowed)`)
+)
+
+// GraphQLOperation 是 schema 或 JS 代码中的一个 GraphQL 操作
+type GraphQLOperation struct {
+	Type       string
+	Name       string
+	Args       []string // 参数名和类型，如 id: ID!
+	ReturnType string
+	Document   string // 可以直接发送的文档，只知道名称时为空
+}
+
+// Body 返回 POST 请求的 JSON 请求体
+func (op *GraphQLOperation) Body() []byte {
+	body, _ := json.Marshal(map[string]any{"query": op.Document, "operationName": op.Name, "variables": map[string]any{}})
+	return body
+}
+
+type gqlTypeRef struct {
+	Kind   string      `json:"kind"`
+	Name   string      `json:"name"`
+	OfType *gqlTypeRef `json:"ofType"`
+}
+
+// String 返回 SDL 格式的类型，如 [String!]!
+func (t *gqlTypeRef) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.Kind == "NON_NULL":
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// named 去掉 NON_NULL 和 LIST，返回最内层的类型
+func (t *gqlTypeRef) named() *gqlTypeRef {
+	for t != nil && t.OfType != nil && (t.Kind == "NON_NULL" || t.Kind == "LIST") {
+		t = t.OfType
+	}
+	return t
+}
+
+type gqlInput struct {
+	Name string      `json:"name"`
+	Type *gqlTypeRef `json:"type"`
+}
+
+type gqlField struct {
+	Name string      `json:"name"`
+	Args []*gqlInput `json:"args"`
+	Type *gqlTypeRef `json:"type"`
+}
+
+type gqlType struct {
+	Kind        string      `json:"kind"`
+	Name        string      `json:"name"`
+	Fields      []*gqlField `json:"fields"`
+	InputFields []*gqlInput `json:"inputFields"`
+	EnumValues  []struct {
+		Name string `json:"name"`
+	} `json:"enumValues"`
+}
+
+type gqlSchema struct {
+	QueryType        *struct{ Name string } `json:"queryType"`
+	MutationType     *struct{ Name string } `json:"mutationType"`
+	SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+	Types            []*gqlType             `json:"types"`
+	types            map[string]*gqlType
+}
+
+// ParseIntrospection 解析内省查询的响应，为每个 query、mutation、subscription 字段生成带有示例参数的文档
+func ParseIntrospection(data []byte) ([]*GraphQLOperation, error) {
+	var resp struct {
+		Data struct {
+			Schema *gqlSchema `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	schema := resp.Data.Schema
+	if schema == nil || len(schema.Types) == 0 {
+		return nil, errors.New("no schema in introspection response")
+	}
+	schema.types = make(map[string]*gqlType, len(schema.Types))
+	for _, t := range schema.Types {
+		schema.types[t.Name] = t
+	}
+
+	var ops []*GraphQLOperation
+	roots := []struct {
+		op   string
+		root *struct{ Name string }
+	}{
+		{GraphQLQuery, schema.QueryType},
+		{GraphQLMutation, schema.MutationType},
+		{GraphQLSubscription, schema.SubscriptionType},
+	}
+	for _, r := range roots {
+		if r.root == nil || schema.types[r.root.Name] == nil {
+			continue
+		}
+		for _, field := range schema.types[r.root.Name].Fields {
+			ops = append(ops, schema.operation(r.op, field))
+		}
+	}
+	return ops, nil
+}
+
+func (s *gqlSchema) operation(opType string, field *gqlField) *GraphQLOperation {
+	op := &GraphQLOperation{Type: opType, Name: field.Name, ReturnType: field.Type.String()}
+	var args []string
+	for _, arg := range field.Args {
+		op.Args = append(op.Args, arg.Name+": "+arg.Type.String())
+		// 只填写必需的参数
+		if arg.Type != nil && arg.Type.Kind == "NON_NULL" {
+			args = append(args, arg.Name+": "+s.value(arg.Type, 0))
+		}
+	}
+	call := field.Name
+	if len(args) > 0 {
+		call += "(" + strings.Join(args, ", ") + ")"
+	}
+	op.Document = fmt.Sprintf("%s %s { %s%s }", opType, field.Name, call, s.selection(field.Type))
+	return op
+}
+
+// value 按类型生成 GraphQL 字面量
+func (s *gqlSchema) value(t *gqlTypeRef, depth int) string {
+	switch {
+	case t == nil:
+		return "null"
+	case t.Kind == "NON_NULL":
+		return s.value(t.OfType, depth)
+	case t.Kind == "LIST":
+		return "[" + s.value(t.OfType, depth) + "]"
+	}
+	switch t.Name {
+	case "Int":
+		return "1"
+	case "Float":
+		return "1.0"
+	case "Boolean":
+		return "true"
+	case "ID":
+		return `"1"`
+	}
+	typ := s.types[t.Name]
+	switch {
+	case typ != nil && typ.Kind == "ENUM" && len(typ.EnumValues) > 0:
+		return typ.EnumValues[0].Name
+	case typ != nil && typ.Kind == "INPUT_OBJECT":
+		if depth >= maxInputDepth {
+			return "{}"
+		}
+		var fields []string
+		for _, f := range typ.InputFields {
+			if f.Type != nil && f.Type.Kind == "NON_NULL" {
+				fields = append(fields, f.Name+": "+s.value(f.Type, depth+1))
+			}
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return `"test"`
+}
+
+// selection 为对象类型生成选择集，只选择不需要参数的标量和枚举字段
+func (s *gqlSchema) selection(t *gqlTypeRef) string {
+	typ := s.types[t.named().Name]
+	if typ == nil || typ.Kind != "OBJECT" && typ.Kind != "INTERFACE" && typ.Kind != "UNION" {
+		return ""
+	}
+	fields := []string{"__typename"}
+	for _, f := range typ.Fields {
+		if len(fields) > maxSelectedField || len(f.Args) > 0 {
+			continue
+		}
+		if ft := s.types[f.Type.named().Name]; ft == nil || ft.Kind == "SCALAR" || ft.Kind == "ENUM" {
+			fields = append(fields, f.Name)
+		}
+	}
+	return " { " + strings.Join(fields, " ") + " }"
+}
+
+// IsGraphQLResponse 根据响应的结构判断是否为 GraphQL 接口的响应
+func IsGraphQLResponse(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return false
+	}
+	if data[0] != '{' {
+		// express-graphql、Apollo Server 对缺少查询的 GET 请求返回纯文本
+		return len(data) < 200 && graphqlErrorRegex.Match(data) && bytes.Contains(bytes.ToLower(data), []byte("query"))
+	}
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message    string          `json:"message"`
+			Locations  json.RawMessage `json:"locations"`
+			Extensions json.RawMessage `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return false
+	}
+	if bytes.Contains(resp.Data, []byte(`"__schema"`)) || bytes.Contains(resp.Data, []byte(`"__typename"`)) {
+		return true
+	}
+	for _, e := range resp.Errors {
+		if e.Locations != nil || graphqlErrorRegex.MatchString(e.Message) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindGraphQLFromJS 获取 JS 代码中的 GraphQL 接口地址，以及 gql 模板字符串、字符串常量和编译后的文档中的操作
+func FindGraphQLFromJS(source string) ([]string, []*GraphQLOperation) {
+	var endpoints []string
+	for _, m := range graphqlEndpointRegex.FindAllStringSubmatch(source, -1) {
+		if !slices.Contains(endpoints, m[1]) {
+			endpoints = append(endpoints, m[1])
+		}
+	}
+
+	text := unescapeJS(source)
+	fragments := make(map[string]string)
+	for _, loc := range graphqlFragmentRegex.FindAllStringSubmatchIndex(text, -1) {
+		if body := matchBraces(text, loc[0]); body != "" {
+			fragments[text[loc[2]:loc[3]]] = body
+		}
+	}
+
+	var ops []*GraphQLOperation
+	exists := func(name string) bool {
+		return slices.ContainsFunc(ops, func(op *GraphQLOperation) bool { return op.Name == name })
+	}
+	for _, loc := range graphqlOperationRegex.FindAllStringSubmatchIndex(text, -1) {
+		name := text[loc[4]:loc[5]]
+		doc := matchBraces(text, loc[0])
+		if doc == "" || exists(name) {
+			continue
+		}
+		ops = append(ops, &GraphQLOperation{Type: text[loc[2]:loc[3]], Name: name, Document: withFragments(doc, fragments)})
+	}
+	for _, m := range graphqlASTRegex.FindAllStringSubmatch(source, -1) {
+		if !exists(m[2]) {
+			ops = append(ops, &GraphQLOperation{Type: m[1], Name: m[2]})
+		}
+	}
+	return endpoints, ops
+}
+
+// withFragments 在文档后面加上其中引用的 fragment 定义
+func withFragments(doc string, fragments map[string]string) string {
+	var names []string
+	var collect func(s string)
+	collect = func(s string) {
+		for _, m := range graphqlSpreadRegex.FindAllStringSubmatch(s, -1) {
+			if f, ok := fragments[m[1]]; ok && !slices.Contains(names, m[1]) {
+				names = append(names, m[1])
+				collect(f)
+			}
+		}
+	}
+	collect(doc)
+	for _, name := range names {
+		doc += " " + fragments[name]
+	}
+	return doc
+}
+
+// matchBraces 返回从 start 开始到第一个花括号块结束的文本，并压缩其中的空白
+func matchBraces(text string, start int) string {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return strings.Join(strings.Fields(text[start:i+1]), " ")
+			}
+		case '"', '\'', '`':
+			// 花括号还没闭合时遇到引号，说明文档已经结束
+			if depth > 0 && text[i] != '"' {
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+// unescapeJS 将 JS 字符串中的转义换行和制表符替换为空格
+func unescapeJS(source string) string {
+	return strings.NewReplacer(`\n`, " ", `\t`, " ", `\r`, " ").Replace(source)
+}
@@ -0,0 +1,99 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+const introspectionResponse = `{"data":{"__schema":{
+	"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},"subscriptionType":null,
+	"types":[
+		{"kind":"OBJECT","name":"Query","fields":[
+			{"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}}},{"name":"locale","type":{"kind":"SCALAR","name":"String"}}],"type":{"kind":"OBJECT","name":"User"}},
+			{"name":"version","args":[],"type":{"kind":"SCALAR","name":"String"}}
+		]},
+		{"kind":"OBJECT","name":"Mutation","fields":[
+			{"name":"createUser","args":[{"name":"input","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"INPUT_OBJECT","name":"UserInput"}}}],"type":{"kind":"OBJECT","name":"User"}}
+		]},
+		{"kind":"OBJECT","name":"User","fields":[
+			{"name":"id","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}}},
+			{"name":"role","args":[],"type":{"kind":"ENUM","name":"Role"}},
+			{"name":"friends","args":[],"type":{"kind":"LIST","name":null,"ofType":{"kind":"OBJECT","name":"User"}}}
+		]},
+		{"kind":"INPUT_OBJECT","name":"UserInput","inputFields":[
+			{"name":"name","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String"}}},
+			{"name":"role","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"ENUM","name":"Role"}}},
+			{"name":"age","type":{"kind":"SCALAR","name":"Int"}}
+		]},
+		{"kind":"ENUM","name":"Role","enumValues":[{"name":"ADMIN"},{"name":"GUEST"}]},
+		{"kind":"SCALAR","name":"ID"},{"kind":"SCALAR","name":"String"},{"kind":"SCALAR","name":"Int"}
+	]}}}`
+
+func TestParseIntrospection(t *testing.T) {
+	ops, err := ParseIntrospection([]byte(introspectionResponse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 3 {
+		t.Fatalf("len(ops) should be 3, not %d", len(ops))
+	}
+
+	expected := []GraphQLOperation{
+		{Type: GraphQLQuery, Name: "user", Args: []string{"id: ID!", "locale: String"}, ReturnType: "User",
+			Document: `query user { user(id: "1") { __typename id role } }`},
+		{Type: GraphQLQuery, Name: "version", ReturnType: "String",
+			Document: `query version { version }`},
+		{Type: GraphQLMutation, Name: "createUser", Args: []string{"input: UserInput!"}, ReturnType: "User",
+			Document: `mutation createUser { createUser(input: {name: "test", role: ADMIN}) { __typename id role } }`},
+	}
+	for i, want := range expected {
+		op := ops[i]
+		if op.Type != want.Type || op.Name != want.Name || op.ReturnType != want.ReturnType ||
+			!slices.Equal(op.Args, want.Args) || op.Document != want.Document {
+			t.Errorf("got %+v, want %+v", op, want)
+		}
+	}
+}
+
+func TestIsGraphQLResponse(t *testing.T) {
+	for body, want := range map[string]bool{
+		`{"errors":[{"message":"Must provide query string."}]}`:                           true,
+		`{"errors":[{"message":"Unexpected token","locations":[{"line":1,"column":2}]}]}`: true,
+		`{"data":{"__typename":"Query"}}`:                                                 true,
+		`{"errors":[{"message":"Introspection is disabled"}]}`:                            true,
+		`{"errors":[{"message":"Syntax Error: Unexpected Name \"foo\"."}]}`:               true,
+		`{"errors":[{"message":"JSON syntax error at position 3"}]}`:                      false,
+		`{"errors":[{"message":"Enable introspection in settings"}]}`:                     false,
+		`GET query missing.`: true,
+		`{"errors":[{"status":"404","title":"Not Found","message":"Resource does not exist"}]}`: false,
+		`{"data":[1,2,3]}`:       false,
+		`<html>Not Found</html>`: false,
+	} {
+		if IsGraphQLResponse([]byte(body)) != want {
+			t.Errorf("IsGraphQLResponse(%s) should be %v", body, want)
+		}
+	}
+}
+
+func TestFindGraphQLFromJS(t *testing.T) {
+	source := "const client=new ApolloClient({uri:\"/api/graphql\"});" +
+		"const Q=gql`\n  query GetUser($id: ID!) {\n    user(id: $id) { ...UserFields }\n  }\n`;" +
+		"const F=\"fragment UserFields on User {\\n  id\\n  name\\n}\";" +
+		`var d={kind:"Document",definitions:[{kind:"OperationDefinition",operation:"mutation",name:{kind:"Name",value:"DeleteUser"}}]};` +
+		`fetch("https://api.example.com/graphql",{method:"POST"});`
+
+	endpoints, ops := FindGraphQLFromJS(source)
+	if !slices.Equal(endpoints, []string{"/api/graphql", "https://api.example.com/graphql"}) {
+		t.Errorf("wrong endpoints: %v", endpoints)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("len(ops) should be 2, not %d", len(ops))
+	}
+	if ops[0].Name != "GetUser" || ops[0].Type != GraphQLQuery ||
+		ops[0].Document != "query GetUser($id: ID!) { user(id: $id) { ...UserFields } } fragment UserFields on User { id name }" {
+		t.Errorf("wrong operation: %+v", ops[0])
+	}
+	if ops[1].Name != "DeleteUser" || ops[1].Type != GraphQLMutation || ops[1].Document != "" {
+		t.Errorf("wrong operation: %+v", ops[1])
+	}
+}
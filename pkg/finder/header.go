@@ -0,0 +1,107 @@
+package finder
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// HeaderLink 是从响应头中提取的链接
+type HeaderLink struct {
+	Header string // 规范格式的响应头名称
+	Link   string // URL 或相对路径，只有主机时为 //host 形式
+	Raw    string
+	Host   bool // 来自 CSP、CORS 等只包含源的响应头
+}
+
+var linkHeaderRegex = regexp.MustCompile(`<([^>]*)>`)
+
+// 包含链接的响应头，CSP 的两个响应头格式相同
+var linkHeaders = []string{
+	"Location",
+	"Content-Location",
+	"Link",
+	"Refresh",
+	"Content-Security-Policy",
+	"Content-Security-Policy-Report-Only",
+	"Access-Control-Allow-Origin",
+}
+
+// FindLinksFromHeaders 从 Location、Link、Refresh、CSP、CORS 等响应头中获取链接和主机
+func FindLinksFromHeaders(header http.Header) []*HeaderLink {
+	var links []*HeaderLink
+	add := func(name, link, raw string, host bool) {
+		if link == "" || slices.ContainsFunc(links, func(l *HeaderLink) bool { return l.Link == link }) {
+			return
+		}
+		links = append(links, &HeaderLink{Header: name, Link: link, Raw: raw, Host: host})
+	}
+
+	for _, name := range linkHeaders {
+		for _, value := range header.Values(name) {
+			value = strings.TrimSpace(value)
+			switch name {
+			case "Location", "Content-Location":
+				add(name, value, value, false)
+			case "Link":
+				for _, m := range linkHeaderRegex.FindAllStringSubmatch(value, -1) {
+					add(name, strings.TrimSpace(m[1]), m[0], false)
+				}
+			case "Refresh":
+				add(name, ParseRefresh(value), value, false)
+			case "Access-Control-Allow-Origin":
+				add(name, sourceHost(value), value, true)
+			default:
+				for _, directive := range strings.Split(value, ";") {
+					fields := strings.Fields(directive)
+					if len(fields) < 2 {
+						continue
+					}
+					switch strings.ToLower(fields[0]) {
+					case "report-uri":
+						for _, uri := range fields[1:] {
+							add(name, uri, uri, false)
+						}
+					case "report-to", "sandbox", "plugin-types", "require-trusted-types-for", "trusted-types":
+					default:
+						for _, source := range fields[1:] {
+							add(name, sourceHost(source), source, true)
+						}
+					}
+				}
+			}
+		}
+	}
+	return links
+}
+
+// sourceHost 将 CSP 或 CORS 中的源转换为 //host 形式的链接，关键字、nonce、hash 和只有协议的源返回空字符串，
+// 通配符子域名返回父域名
+func sourceHost(source string) string {
+	source = strings.Trim(source, `'"`)
+	if source == "" || source == "*" || source == "null" || strings.HasSuffix(source, ":") ||
+		source == "self" || source == "none" || strings.HasPrefix(source, "unsafe-") || source == "strict-dynamic" ||
+		strings.HasPrefix(source, "nonce-") || strings.HasPrefix(source, "sha256-") ||
+		strings.HasPrefix(source, "sha384-") || strings.HasPrefix(source, "sha512-") {
+		return ""
+	}
+	scheme, host, found := strings.Cut(source, "://")
+	if !found {
+		scheme, host = "", source
+	}
+	host, _, _ = strings.Cut(host, "/")
+	host = strings.TrimPrefix(host, "*.")
+	if host == "" || strings.ContainsAny(host, "*'") {
+		return ""
+	}
+	switch strings.ToLower(scheme) {
+	case "":
+		return "//" + host + "/"
+	case "ws":
+		scheme = "http"
+	case "wss":
+		scheme = "https"
+	}
+	return scheme + "://" + host + "/"
+}
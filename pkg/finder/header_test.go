@@ -0,0 +1,35 @@
+package finder
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestFindLinksFromHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Location", "/login?next=%2F")
+	header.Add("Link", `</static/app.css>; rel=preload; as=style, <https://example.com/page/2>; rel="next"`)
+	header.Set("Refresh", "3; url=/home")
+	header.Set("Content-Security-Policy", "default-src 'self'; script-src 'nonce-abc' https://*.cdn.example.com data:; connect-src api.example.com wss://ws.example.com; report-uri /csp-report")
+	header.Set("Access-Control-Allow-Origin", "https://admin.example.com")
+
+	var links []string
+	for _, l := range FindLinksFromHeaders(header) {
+		links = append(links, l.Link)
+	}
+	want := []string{
+		"/login?next=%2F",
+		"/static/app.css",
+		"https://example.com/page/2",
+		"/home",
+		"https://cdn.example.com/",
+		"//api.example.com/",
+		"https://ws.example.com/",
+		"/csp-report",
+		"https://admin.example.com/",
+	}
+	if !slices.Equal(links, want) {
+		t.Errorf("got %v, want %v", links, want)
+	}
+}
@@ -0,0 +1,138 @@
+package finder
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTMLSource 描述从一类 HTML 元素中提取链接的方法
+type HTMLSource struct {
+	Name     string // 用于启用或禁用该来源
+	Selector string
+	Extract  func(s *goquery.Selection) []string
+}
+
+// HTML 链接来源的名称
+const (
+	HTMLLink   = "link"
+	HTMLIframe = "iframe"
+	HTMLArea   = "area"
+	HTMLBase   = "base"
+	HTMLMeta   = "meta"
+	HTMLSrcset = "srcset"
+	HTMLScript = "script"
+	HTMLEvent  = "event"
+	HTMLData   = "data"
+)
+
+// HTMLSources 是 a[href]、script[src]、form[action] 之外的 HTML 链接来源，
+// 相对链接应该由调用者根据 <base href> 解析
+var HTMLSources = []*HTMLSource{
+	{Name: HTMLLink, Selector: "link[href]", Extract: attrExtractor("href")},
+	{Name: HTMLIframe, Selector: "iframe[src], frame[src]", Extract: attrExtractor("src")},
+	{Name: HTMLArea, Selector: "area[href]", Extract: attrExtractor("href")},
+	{Name: HTMLBase, Selector: "base[href]", Extract: attrExtractor("href")},
+	{Name: HTMLMeta, Selector: "meta[http-equiv][content]", Extract: extractMetaRefresh},
+	{Name: HTMLSrcset, Selector: "img[srcset], source[srcset]", Extract: extractSrcset},
+	{Name: HTMLScript, Selector: "script:not([src]):not([type=importmap])", Extract: extractInlineScript},
+	{Name: HTMLEvent, Selector: "[onclick], [onsubmit], [onchange], [onmouseover]", Extract: extractEventHandlers},
+	{Name: HTMLData, Selector: "*", Extract: extractDataAttrs},
+}
+
+// HTMLSourceNames 返回所有 HTML 链接来源的名称
+func HTMLSourceNames() []string {
+	names := make([]string, 0, len(HTMLSources))
+	for _, src := range HTMLSources {
+		names = append(names, src.Name)
+	}
+	return names
+}
+
+var (
+	refreshRegex  = regexp.MustCompile(`(?i)^\s*\d*(?:\.\d*)?\s*[;,]?\s*(?:url\s*=\s*)?['"]?([^'"]+)['"]?\s*$`)
+	dataAttrRegex = regexp.MustCompile(`(?i)^data-(?:[\w-]*-)?(?:url|uri|href|src|action|endpoint|api|link|path)$`)
+)
+
+func attrExtractor(name string) func(s *goquery.Selection) []string {
+	return func(s *goquery.Selection) []string {
+		if v := strings.TrimSpace(s.AttrOr(name, "")); isLinkValue(v) {
+			return []string{v}
+		}
+		return nil
+	}
+}
+
+// extractMetaRefresh 解析 <meta http-equiv="refresh" content="5; url=/next">
+func extractMetaRefresh(s *goquery.Selection) []string {
+	if !strings.EqualFold(s.AttrOr("http-equiv", ""), "refresh") {
+		return nil
+	}
+	if link := ParseRefresh(s.AttrOr("content", "")); link != "" {
+		return []string{link}
+	}
+	return nil
+}
+
+// ParseRefresh 返回 Refresh 头或 meta refresh 中的跳转地址
+func ParseRefresh(content string) string {
+	m := refreshRegex.FindStringSubmatch(content)
+	if m == nil || !strings.ContainsAny(content, ";,=") {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+// extractSrcset 解析 srcset 中的每个候选地址，地址后面是可选的宽度或像素密度描述
+func extractSrcset(s *goquery.Selection) []string {
+	var links []string
+	for _, candidate := range strings.Split(s.AttrOr("srcset", ""), ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 && isLinkValue(fields[0]) && !slices.Contains(links, fields[0]) {
+			links = append(links, fields[0])
+		}
+	}
+	return links
+}
+
+func extractInlineScript(s *goquery.Selection) []string {
+	return FindLinksFromJS(s.Text())
+}
+
+func extractEventHandlers(s *goquery.Selection) []string {
+	var links []string
+	for _, attr := range s.Nodes[0].Attr {
+		if strings.HasPrefix(strings.ToLower(attr.Key), "on") {
+			links = append(links, FindLinksFromJS(attr.Val)...)
+		}
+	}
+	return links
+}
+
+// extractDataAttrs 从名称类似 data-url、data-api-endpoint 的属性中提取链接
+func extractDataAttrs(s *goquery.Selection) []string {
+	var links []string
+	for _, attr := range s.Nodes[0].Attr {
+		if !dataAttrRegex.MatchString(attr.Key) {
+			continue
+		}
+		if v := strings.TrimSpace(attr.Val); isLinkValue(v) && !slices.Contains(links, v) {
+			links = append(links, v)
+		}
+	}
+	return links
+}
+
+// isLinkValue 排除空值、锚点以及 javascript:、data: 等不能访问的地址
+func isLinkValue(v string) bool {
+	if v == "" || strings.HasPrefix(v, "#") {
+		return false
+	}
+	scheme, _, found := strings.Cut(strings.ToLower(v), ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	return scheme == "http" || scheme == "https"
+}
@@ -0,0 +1,63 @@
+package finder
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestHTMLSources(t *testing.T) {
+	html := `<html><head>
+	<base href="/app/">
+	<link rel="stylesheet" href="css/main.css">
+	<meta http-equiv="Refresh" content="5; URL='/next.html'">
+	<script>var api = "/api/v1/users";</script>
+	<script type="importmap">{"imports": {"vue": "/libs/vue.js"}}</script>
+</head><body>
+	<iframe src="https://example.com/embed"></iframe>
+	<map><area href="/area.html"><area href="javascript:void(0)"></map>
+	<img srcset="/img/a.png 1x, /img/b.png 2x">
+	<button onclick="location.href='/go/admin'">Go</button>
+	<div data-url="/data/load" data-api-endpoint="/api/v2/items" data-id="12"></div>
+</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		HTMLLink:   {"css/main.css"},
+		HTMLIframe: {"https://example.com/embed"},
+		HTMLArea:   {"/area.html"},
+		HTMLBase:   {"/app/"},
+		HTMLMeta:   {"/next.html"},
+		HTMLSrcset: {"/img/a.png", "/img/b.png"},
+		HTMLScript: {"/api/v1/users"},
+		HTMLEvent:  {"/go/admin"},
+		HTMLData:   {"/data/load", "/api/v2/items"},
+	}
+	for _, src := range HTMLSources {
+		var links []string
+		doc.Find(src.Selector).Each(func(_ int, s *goquery.Selection) {
+			links = append(links, src.Extract(s)...)
+		})
+		if !slices.Equal(links, expected[src.Name]) {
+			t.Errorf("%s: got %v, want %v", src.Name, links, expected[src.Name])
+		}
+	}
+}
+
+func TestParseRefresh(t *testing.T) {
+	for content, want := range map[string]string{
+		"0;url=/a":          "/a",
+		"3, URL = '/b?x=1'": "/b?x=1",
+		"5":                 "",
+		"url=http://c.com/": "http://c.com/",
+	} {
+		if got := ParseRefresh(content); got != want {
+			t.Errorf("ParseRefresh(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
@@ -75,6 +75,8 @@ func FindLinksFromJS(source string) []string {
 }
 
 // FindDynamicLinks 通过动态生成并执行 JS 代码，获取 Webpack 打包的资源路径
+//
+// Deprecated: 使用不依赖浏览器的 FindChunksFromJS
 func FindDynamicLinksFromJS(source string, browser *rod.Browser) []string {
 	var endpoints []string
 	jsRegex := regexp.MustCompile(`\w\.p\+"(.*?)\.js`)
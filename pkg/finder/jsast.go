@@ -0,0 +1,642 @@
+package finder
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/parser"
+	jstoken "github.com/dop251/goja/token"
+)
+
+// 发出请求的 HTTP 客户端
+const (
+	ClientFetch  = "fetch"
+	ClientAxios  = "axios"
+	ClientJQuery = "jquery"
+	ClientXHR    = "xhr"
+)
+
+// 从 JS 代码中提取的 URL 的可信度
+const (
+	ConfidenceHigh   = "high"   // 客户端调用中完整计算出的 URL
+	ConfidenceMedium = "medium" // 客户端调用中部分计算出的 URL，或由常量拼接得到的路径
+	ConfidenceLow    = "low"    // 只是看起来像路径的字符串
+)
+
+// 源码片段的最大长度
+const maxRawLength = 200
+
+var (
+	astPackage  = reflect.TypeOf(ast.Program{}).PkgPath()
+	httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	// 无法确定值的变量定义，如函数参数
+	unknownDef = &ast.NullLiteral{}
+)
+
+// Endpoint 是通过语法分析从 JS 代码中提取的请求
+type Endpoint struct {
+	URL        string
+	Method     string
+	Params     []string // 查询参数和请求体中的参数名
+	JSON       bool     // 请求体是 JSON 格式
+	Client     string   // 发出请求的客户端，不是客户端调用时为空
+	Confidence string
+	Raw        string // 请求所在的源码片段
+}
+
+// FindEndpointsFromJS 解析 JS 代码，计算常量拼接和模板字符串得到的路径，并识别 fetch、axios、
+// jQuery.ajax 和 XMLHttpRequest.open 等调用的 URL、请求方法和参数。无法解析的代码返回错误
+func FindEndpointsFromJS(source string) ([]*Endpoint, error) {
+	program, err := parser.ParseFile(nil, "", source, 0, parser.WithDisableSourceMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &jsAnalyzer{
+		source:  source,
+		defs:    make(map[string][]ast.Expression),
+		clients: map[string]string{"axios": ""},
+		visited: make(map[ast.Node]bool),
+	}
+	walkAST(program, a.define)
+	walkAST(program, a.defineClient)
+	walkAST(program, a.analyze)
+	return a.endpoints, nil
+}
+
+// jsAnalyzer 保存分析过程中的变量定义和 axios 实例
+type jsAnalyzer struct {
+	source    string
+	defs      map[string][]ast.Expression
+	clients   map[string]string // axios 实例的变量名 -> baseURL
+	visited   map[ast.Node]bool
+	endpoints []*Endpoint
+}
+
+// define 记录变量的所有赋值，只有唯一赋值的变量才被视为常量
+func (a *jsAnalyzer) define(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.ParameterList:
+		// 压缩后的代码中参数经常与外层的常量同名
+		for _, b := range n.List {
+			if id, ok := b.Target.(*ast.Identifier); ok {
+				a.addDef(id.Name.String(), unknownDef)
+			}
+		}
+	case *ast.Binding:
+		if id, ok := n.Target.(*ast.Identifier); ok && n.Initializer != nil {
+			a.addDef(id.Name.String(), n.Initializer)
+		}
+	case *ast.AssignExpression:
+		if id, ok := n.Left.(*ast.Identifier); ok {
+			if n.Operator != jstoken.ASSIGN {
+				// a += "..." 等运算使变量不再是常量
+				a.addDef(id.Name.String(), unknownDef)
+			}
+			a.addDef(id.Name.String(), n.Right)
+		}
+	}
+}
+
+func (a *jsAnalyzer) addDef(name string, expr ast.Expression) {
+	if !slices.Contains(a.defs[name], expr) {
+		a.defs[name] = append(a.defs[name], expr)
+	}
+}
+
+// defineClient 记录 axios.create 创建的实例和 axios.defaults.baseURL
+func (a *jsAnalyzer) defineClient(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Binding:
+		if id, ok := n.Target.(*ast.Identifier); ok {
+			if base, ok := a.axiosCreate(n.Initializer); ok {
+				a.clients[id.Name.String()] = base
+			}
+		}
+	case *ast.AssignExpression:
+		if id, ok := n.Left.(*ast.Identifier); ok {
+			if base, ok := a.axiosCreate(n.Right); ok {
+				a.clients[id.Name.String()] = base
+			}
+		}
+		// axios.defaults.baseURL = "..."
+		if dot, ok := n.Left.(*ast.DotExpression); ok && dot.Identifier.Name == "baseURL" {
+			if defaults, ok := dot.Left.(*ast.DotExpression); ok && defaults.Identifier.Name == "defaults" {
+				if name := identName(defaults.Left); name != "" {
+					if _, known := a.clients[name]; known {
+						a.clients[name], _ = a.eval(n.Right)
+					}
+				}
+			}
+		}
+	}
+}
+
+// axiosCreate 判断表达式是否为 axios.create(config)，返回 config 中的 baseURL
+func (a *jsAnalyzer) axiosCreate(expr ast.Expression) (string, bool) {
+	call, ok := expr.(*ast.CallExpression)
+	if !ok {
+		return "", false
+	}
+	dot, ok := call.Callee.(*ast.DotExpression)
+	if !ok || dot.Identifier.Name != "create" || identName(dot.Left) != "axios" {
+		return "", false
+	}
+	if len(call.ArgumentList) == 0 {
+		return "", true
+	}
+	base, _ := a.eval(a.property(call.ArgumentList[0], "baseURL"))
+	return base, true
+}
+
+// analyze 识别客户端调用和拼接得到的路径
+func (a *jsAnalyzer) analyze(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.CallExpression:
+		a.analyzeCall(n)
+	case *ast.BinaryExpression, *ast.TemplateLiteral:
+		if a.visited[n] {
+			return
+		}
+		a.markVisited(n)
+		if !a.isComputed(n.(ast.Expression)) {
+			return
+		}
+		if url, _ := a.eval(n.(ast.Expression)); isPathLike(url) {
+			a.add(&Endpoint{URL: url, Method: "GET", Confidence: ConfidenceMedium}, n)
+		}
+	}
+}
+
+func (a *jsAnalyzer) analyzeCall(call *ast.CallExpression) {
+	args := call.ArgumentList
+	if len(args) == 0 {
+		return
+	}
+	var ep *Endpoint
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		switch name := callee.Name.String(); {
+		case name == "fetch":
+			ep = a.fetchCall(args)
+		case a.isClient(name):
+			// axios(config) 或 axios(url, config)
+			ep = a.axiosCall(name, "", args)
+		}
+	case *ast.DotExpression:
+		object, method := identName(callee.Left), callee.Identifier.Name.String()
+		switch {
+		case method == "fetch" && (object == "window" || object == "self" || object == "globalThis"):
+			ep = a.fetchCall(args)
+		case object == "$" || object == "jQuery":
+			ep = a.jqueryCall(method, args)
+		case method == "open" && len(args) >= 2:
+			ep = a.xhrCall(args)
+		case a.isClient(object) || isClientMethod(method):
+			ep = a.axiosCall(object, method, args)
+		}
+	}
+	if ep == nil || !isPathLike(ep.URL) {
+		return
+	}
+	if ep.Confidence == "" {
+		ep.Confidence = ConfidenceHigh
+	}
+	a.add(ep, call)
+}
+
+// fetchCall 解析 fetch(url, {method, body, headers})
+func (a *jsAnalyzer) fetchCall(args []ast.Expression) *Endpoint {
+	ep := a.newEndpoint(ClientFetch, args[0])
+	if len(args) > 1 {
+		if method, ok := a.eval(a.property(args[1], "method")); ok && method != "" {
+			ep.Method = strings.ToUpper(method)
+		}
+		ep.Params, ep.JSON = a.bodyParams(a.property(args[1], "body"))
+	}
+	return ep
+}
+
+// axiosCall 解析 axios(config)、axios.get(url, config)、axios.post(url, data, config) 和 axios 实例的调用
+func (a *jsAnalyzer) axiosCall(object, method string, args []ast.Expression) *Endpoint {
+	base := a.clients[object]
+	var ep *Endpoint
+	var config ast.Expression
+	switch method {
+	case "", "request":
+		if _, ok := args[0].(*ast.ObjectLiteral); ok || method == "request" {
+			config = args[0]
+			ep = a.newEndpoint(ClientAxios, a.property(config, "url"))
+		} else {
+			ep = a.newEndpoint(ClientAxios, args[0])
+			if len(args) > 1 {
+				config = args[1]
+			}
+		}
+		if m, ok := a.eval(a.property(config, "method")); ok && m != "" {
+			ep.Method = strings.ToUpper(m)
+		}
+		ep.Params, ep.JSON = a.objectParams(a.property(config, "data"))
+	default:
+		ep = a.newEndpoint(ClientAxios, args[0])
+		ep.Method = strings.ToUpper(method)
+		if method == "post" || method == "put" || method == "patch" {
+			if len(args) > 1 {
+				ep.Params, ep.JSON = a.objectParams(args[1])
+			}
+			if len(args) > 2 {
+				config = args[2]
+			}
+		} else if len(args) > 1 {
+			config = args[1]
+		}
+	}
+	if config != nil {
+		params, _ := a.objectParams(a.property(config, "params"))
+		ep.Params = append(ep.Params, params...)
+		if b, ok := a.eval(a.property(config, "baseURL")); ok {
+			base = b
+		}
+	}
+	if !a.isClient(object) {
+		// 只根据方法名判断的客户端可能是 Map.get 等无关的调用
+		ep.Confidence = ConfidenceMedium
+	}
+	if ep.URL != "" && !isAbsolute(ep.URL) {
+		ep.URL = joinURL(base, ep.URL)
+	}
+	return ep
+}
+
+// jqueryCall 解析 $.ajax(url|settings)、$.get(url, data) 和 $.post(url, data)
+func (a *jsAnalyzer) jqueryCall(method string, args []ast.Expression) *Endpoint {
+	switch method {
+	case "ajax":
+		settings := args[0]
+		var ep *Endpoint
+		if _, ok := args[0].(*ast.ObjectLiteral); ok {
+			ep = a.newEndpoint(ClientJQuery, a.property(settings, "url"))
+		} else {
+			ep = a.newEndpoint(ClientJQuery, args[0])
+			settings = nil
+			if len(args) > 1 {
+				settings = args[1]
+			}
+		}
+		for _, key := range []string{"method", "type"} {
+			if m, ok := a.eval(a.property(settings, key)); ok && m != "" {
+				ep.Method = strings.ToUpper(m)
+			}
+		}
+		ep.Params, ep.JSON = a.bodyParams(a.property(settings, "data"))
+		return ep
+	case "get", "post", "getJSON":
+		ep := a.newEndpoint(ClientJQuery, args[0])
+		if method == "post" {
+			ep.Method = "POST"
+		}
+		if len(args) > 1 {
+			ep.Params, _ = a.objectParams(args[1])
+		}
+		return ep
+	}
+	return nil
+}
+
+// xhrCall 解析 xhr.open(method, url)
+func (a *jsAnalyzer) xhrCall(args []ast.Expression) *Endpoint {
+	method, ok := a.eval(args[0])
+	if !ok || !slices.Contains(httpMethods, strings.ToUpper(method)) {
+		return nil
+	}
+	ep := a.newEndpoint(ClientXHR, args[1])
+	ep.Method = strings.ToUpper(method)
+	return ep
+}
+
+func (a *jsAnalyzer) newEndpoint(client string, url ast.Expression) *Endpoint {
+	ep := &Endpoint{Method: "GET", Client: client}
+	a.markVisited(url)
+	value, complete := a.eval(url)
+	ep.URL = value
+	if !complete {
+		ep.Confidence = ConfidenceMedium
+	}
+	return ep
+}
+
+// bodyParams 获取请求体中的参数名，支持对象字面量、JSON.stringify、URLSearchParams 和 FormData
+func (a *jsAnalyzer) bodyParams(expr ast.Expression) ([]string, bool) {
+	switch e := a.deref(expr).(type) {
+	case *ast.CallExpression:
+		if dot, ok := e.Callee.(*ast.DotExpression); ok && len(e.ArgumentList) > 0 {
+			switch identName(dot.Left) + "." + dot.Identifier.Name.String() {
+			case "JSON.stringify":
+				params, _ := a.objectParams(e.ArgumentList[0])
+				return params, true
+			case "qs.stringify", "Qs.stringify", "$.param", "jQuery.param":
+				params, _ := a.objectParams(e.ArgumentList[0])
+				return params, false
+			}
+		}
+	case *ast.NewExpression:
+		if identName(e.Callee) == "URLSearchParams" && len(e.ArgumentList) > 0 {
+			params, _ := a.objectParams(e.ArgumentList[0])
+			return params, false
+		}
+	case *ast.StringLiteral:
+		// a=1&b=2
+		var params []string
+		for _, pair := range strings.Split(e.Value.String(), "&") {
+			if name, _, _ := strings.Cut(pair, "="); name != "" {
+				params = append(params, name)
+			}
+		}
+		return params, false
+	case *ast.ObjectLiteral:
+		params, _ := a.objectParams(e)
+		return params, false
+	}
+	return nil, false
+}
+
+// objectParams 获取对象字面量的属性名，对象会被序列化为 JSON
+func (a *jsAnalyzer) objectParams(expr ast.Expression) ([]string, bool) {
+	obj, ok := a.deref(expr).(*ast.ObjectLiteral)
+	if !ok {
+		return nil, false
+	}
+	var params []string
+	for _, prop := range obj.Value {
+		switch p := prop.(type) {
+		case *ast.PropertyKeyed:
+			if key, ok := a.propertyKey(p); ok {
+				params = append(params, key)
+			}
+		case *ast.PropertyShort:
+			params = append(params, p.Name.Name.String())
+		}
+	}
+	return params, len(params) > 0
+}
+
+// property 返回对象字面量中属性的值
+func (a *jsAnalyzer) property(expr ast.Expression, name string) ast.Expression {
+	obj, ok := a.deref(expr).(*ast.ObjectLiteral)
+	if !ok {
+		return nil
+	}
+	for _, prop := range obj.Value {
+		switch p := prop.(type) {
+		case *ast.PropertyKeyed:
+			if key, ok := a.propertyKey(p); ok && key == name {
+				return p.Value
+			}
+		case *ast.PropertyShort:
+			if p.Name.Name.String() == name {
+				return &p.Name
+			}
+		}
+	}
+	return nil
+}
+
+func (a *jsAnalyzer) propertyKey(p *ast.PropertyKeyed) (string, bool) {
+	if p.Kind != ast.PropertyKindValue {
+		return "", false
+	}
+	if !p.Computed {
+		switch k := p.Key.(type) {
+		case *ast.Identifier:
+			return k.Name.String(), true
+		case *ast.StringLiteral:
+			return k.Value.String(), true
+		}
+	}
+	return a.eval(p.Key)
+}
+
+// deref 返回常量引用的表达式
+func (a *jsAnalyzer) deref(expr ast.Expression) ast.Expression {
+	for i := 0; i < 8; i++ {
+		id, ok := expr.(*ast.Identifier)
+		if !ok {
+			return expr
+		}
+		defs := a.defs[id.Name.String()]
+		if len(defs) != 1 {
+			return expr
+		}
+		expr = defs[0]
+	}
+	return expr
+}
+
+// eval 计算字符串表达式的值，complete 为 false 时返回能够确定的前缀
+func (a *jsAnalyzer) eval(expr ast.Expression) (value string, complete bool) {
+	return a.evalDepth(expr, 0)
+}
+
+func (a *jsAnalyzer) evalDepth(expr ast.Expression, depth int) (string, bool) {
+	// 避免循环引用
+	if expr == nil || depth > 16 {
+		return "", false
+	}
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return e.Value.String(), true
+	case *ast.NumberLiteral:
+		return e.Literal, true
+	case *ast.TemplateLiteral:
+		if e.Tag != nil {
+			return "", false
+		}
+		var sb strings.Builder
+		for i, el := range e.Elements {
+			sb.WriteString(el.Parsed.String())
+			if i < len(e.Expressions) {
+				v, ok := a.evalDepth(e.Expressions[i], depth+1)
+				sb.WriteString(v)
+				if !ok {
+					return sb.String(), false
+				}
+			}
+		}
+		return sb.String(), true
+	case *ast.BinaryExpression:
+		if e.Operator != jstoken.PLUS {
+			return "", false
+		}
+		left, ok := a.evalDepth(e.Left, depth+1)
+		if !ok {
+			return left, false
+		}
+		right, ok := a.evalDepth(e.Right, depth+1)
+		return left + right, ok
+	case *ast.Identifier:
+		var values []string
+		for _, def := range a.defs[e.Name.String()] {
+			v, ok := a.evalDepth(def, depth+1)
+			if !ok {
+				return "", false
+			}
+			if !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) != 1 {
+			return "", false
+		}
+		return values[0], true
+	case *ast.DotExpression:
+		// 常量对象的属性，如 API.base
+		if obj, ok := a.deref(e.Left).(*ast.ObjectLiteral); ok {
+			return a.evalDepth(a.property(obj, e.Identifier.Name.String()), depth+1)
+		}
+	case *ast.CallExpression:
+		// Babel 将模板字符串编译为 "".concat(a, "/b")
+		if dot, ok := e.Callee.(*ast.DotExpression); ok && dot.Identifier.Name == "concat" {
+			v, ok := a.evalDepth(dot.Left, depth+1)
+			if !ok {
+				return v, false
+			}
+			for _, arg := range e.ArgumentList {
+				s, ok := a.evalDepth(arg, depth+1)
+				v += s
+				if !ok {
+					return v, false
+				}
+			}
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// isComputed 判断表达式是否由多个部分拼接而成，单个字符串常量由正则处理
+func (a *jsAnalyzer) isComputed(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.BinaryExpression:
+		return e.Operator == jstoken.PLUS
+	case *ast.TemplateLiteral:
+		return e.Tag == nil && len(e.Expressions) > 0
+	}
+	return false
+}
+
+// markVisited 标记 URL 表达式中的子表达式，避免重复输出拼接的中间结果
+func (a *jsAnalyzer) markVisited(expr ast.Node) {
+	if expr == nil {
+		return
+	}
+	walkAST(expr, func(n ast.Node) {
+		a.visited[n] = true
+	})
+}
+
+func (a *jsAnalyzer) isClient(name string) bool {
+	_, ok := a.clients[name]
+	return ok
+}
+
+func (a *jsAnalyzer) add(ep *Endpoint, n ast.Node) {
+	ep.Raw = a.snippet(n)
+	for _, o := range a.endpoints {
+		if o.URL == ep.URL && o.Method == ep.Method {
+			// 保留可信度更高的结果
+			if o.Client == "" && ep.Client != "" {
+				*o = *ep
+			}
+			return
+		}
+	}
+	a.endpoints = append(a.endpoints, ep)
+}
+
+// snippet 返回节点对应的源码
+func (a *jsAnalyzer) snippet(n ast.Node) string {
+	// 文件的起始位置为 1
+	from, to := int(n.Idx0())-1, int(n.Idx1())-1
+	if from < 0 || to > len(a.source) || from >= to {
+		return ""
+	}
+	raw := a.source[from:to]
+	if len(raw) > maxRawLength {
+		raw = raw[:maxRawLength] + "..."
+	}
+	return raw
+}
+
+func isClientMethod(method string) bool {
+	return slices.Contains([]string{"get", "post", "put", "delete", "patch", "head", "options", "request"}, method)
+}
+
+// isPathLike 判断计算出的字符串是否像 URL 或路径
+func isPathLike(s string) bool {
+	if s == "" || strings.ContainsAny(s, " \t\n<>{}\"'") {
+		return false
+	}
+	if isAbsolute(s) {
+		return true
+	}
+	return strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") && len(s) > 1 ||
+		strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") ||
+		strings.Contains(s, "/") && !strings.HasSuffix(s, "/") && strings.IndexByte(s, '/') > 0
+}
+
+func isAbsolute(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") ||
+		strings.HasPrefix(s, "ws://") || strings.HasPrefix(s, "wss://")
+}
+
+// joinURL 拼接 baseURL 和相对路径
+func joinURL(base, path string) string {
+	if base == "" {
+		return path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func identName(expr ast.Expression) string {
+	if id, ok := expr.(*ast.Identifier); ok {
+		return id.Name.String()
+	}
+	return ""
+}
+
+// walkAST 按深度优先的顺序对语法树中的每个节点调用 fn
+func walkAST(n ast.Node, fn func(ast.Node)) {
+	walkValue(reflect.ValueOf(n), fn)
+}
+
+func walkValue(v reflect.Value, fn func(ast.Node)) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			walkValue(v.Elem(), fn)
+		}
+	case reflect.Pointer:
+		if v.IsNil() || v.Type().Elem().PkgPath() != astPackage || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		if n, ok := v.Interface().(ast.Node); ok {
+			fn(n)
+		}
+		walkValue(v.Elem(), fn)
+	case reflect.Struct:
+		if v.Type().PkgPath() != astPackage {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkValue(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkValue(v.Index(i), fn)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindEndpointsFromJS(t *testing.T) {
+	source := `
+const base = "/api/v1";
+const api = axios.create({baseURL: "/gateway"});
+function load(id, e) {
+	fetch(base + "/users/" + id);
+	fetch(` + "`${base}/orders`" + `, {method: "post", body: JSON.stringify({sku: e, count: 1})});
+	api.get("/profile", {params: {lang: "en"}});
+	axios({url: "/api/login", method: "PUT", data: {username: "a", password: "b"}});
+	$.ajax({url: "".concat(base, "/search"), type: "POST", data: {q: e}});
+	var xhr = new XMLHttpRequest();
+	xhr.open("DELETE", base + "/items");
+	var cache = new Map();
+	cache.get("key");
+	var link = base + "/help";
+}
+`
+	endpoints, err := FindEndpointsFromJS(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		method, client, confidence string
+		params                     []string
+		json                       bool
+	}
+	tests := map[string]want{
+		"/api/v1/users/":   {"GET", ClientFetch, ConfidenceMedium, nil, false},
+		"/api/v1/orders":   {"POST", ClientFetch, ConfidenceHigh, []string{"sku", "count"}, true},
+		"/gateway/profile": {"GET", ClientAxios, ConfidenceHigh, []string{"lang"}, false},
+		"/api/login":       {"PUT", ClientAxios, ConfidenceHigh, []string{"username", "password"}, true},
+		"/api/v1/search":   {"POST", ClientJQuery, ConfidenceHigh, []string{"q"}, false},
+		"/api/v1/items":    {"DELETE", ClientXHR, ConfidenceHigh, nil, false},
+		"/api/v1/help":     {"GET", "", ConfidenceMedium, nil, false},
+	}
+	if len(endpoints) != len(tests) {
+		for _, ep := range endpoints {
+			t.Logf("%+v", ep)
+		}
+		t.Fatalf("got %d endpoints, want %d", len(endpoints), len(tests))
+	}
+	for _, ep := range endpoints {
+		w, ok := tests[ep.URL]
+		if !ok {
+			t.Errorf("unexpected endpoint: %+v", ep)
+			continue
+		}
+		if ep.Method != w.method || ep.Client != w.client || ep.Confidence != w.confidence ||
+			!slices.Equal(ep.Params, w.params) || ep.JSON != w.json {
+			t.Errorf("%s: got %+v, want %+v", ep.URL, ep, w)
+		}
+	}
+}
+
+func TestFindEndpointsFromJSShadowed(t *testing.T) {
+	// 参数与常量同名时不能使用常量的值
+	source := `var e = "/static"; function f(e) { return fetch(e + "/data") }`
+	endpoints, err := FindEndpointsFromJS(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 0 {
+		t.Errorf("unexpected endpoints: %+v", endpoints[0])
+	}
+}
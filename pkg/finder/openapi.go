@@ -0,0 +1,369 @@
+package finder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	base "github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// 生成示例数据时 schema 的最大嵌套层数，避免循环引用导致无限递归
+const maxSchemaDepth = 5
+
+// findLinksFromOpenAPI 解析 OpenAPI 3.0/3.1 文档
+func findLinksFromOpenAPI(doc libopenapi.Document) ([]*API, error) {
+	v3Model, errs := doc.BuildV3Model()
+	// 循环引用等问题也会产生错误，但模型本身通常仍然可用
+	if v3Model == nil {
+		return nil, fmt.Errorf("cannot create v3 model from document: %d errors reported", len(errs))
+	}
+
+	model := v3Model.Model
+	if model.Paths == nil || model.Paths.PathItems == nil {
+		return nil, errors.New("there is no paths in document")
+	}
+
+	var result []*API
+	for pathPair := model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+		pathItem := pathPair.Value()
+
+		for op := pathItem.GetOperations().First(); op != nil; op = op.Next() {
+			operation := op.Value()
+
+			// 作用域由小到大：operation > path > document
+			servers := operation.Servers
+			if len(servers) == 0 {
+				servers = pathItem.Servers
+			}
+			if len(servers) == 0 {
+				servers = model.Servers
+			}
+
+			params := mergeParameters(pathItem.Parameters, operation.Parameters)
+			for _, server := range serverURLs(servers) {
+				api := &API{
+					Method:  op.Key(),
+					URL:     joinServerPath(server, pathPair.Key()),
+					Headers: make(map[string]string),
+				}
+				applyParameters(api, params)
+				applyRequestBody(api, operation.RequestBody)
+				result = append(result, api)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// serverURLs 使用变量默认值展开 servers 列表，没有定义时返回相对根路径
+func serverURLs(servers []*v3.Server) []string {
+	var urls []string
+	for _, s := range servers {
+		if s == nil {
+			continue
+		}
+		u := s.URL
+		if s.Variables != nil {
+			for v := s.Variables.First(); v != nil; v = v.Next() {
+				value := v.Value().Default
+				if value == "" && len(v.Value().Enum) > 0 {
+					value = v.Value().Enum[0]
+				}
+				u = strings.ReplaceAll(u, "{"+v.Key()+"}", value)
+			}
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		urls = append(urls, "")
+	}
+	return urls
+}
+
+func joinServerPath(server, path string) string {
+	if server == "" {
+		return path
+	}
+	return strings.TrimRight(server, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// mergeParameters 合并 path 和 operation 中定义的参数，operation 中同名同位置的参数优先
+func mergeParameters(pathParams, opParams []*v3.Parameter) []*v3.Parameter {
+	var merged []*v3.Parameter
+	for _, p := range pathParams {
+		overridden := false
+		for _, o := range opParams {
+			if o != nil && p != nil && o.Name == p.Name && o.In == p.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden && p != nil {
+			merged = append(merged, p)
+		}
+	}
+	for _, o := range opParams {
+		if o != nil {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+func applyParameters(api *API, params []*v3.Parameter) {
+	var cookies []string
+	for _, param := range params {
+		value := parameterValue(param)
+
+		switch param.In {
+		case "path":
+			api.URL = strings.ReplaceAll(api.URL, fmt.Sprintf("{%s}", param.Name), url.PathEscape(value))
+		case "query":
+			pair := url.QueryEscape(param.Name) + "=" + url.QueryEscape(value)
+			if !strings.Contains(api.URL, "?") {
+				api.URL += "?" + pair
+			} else {
+				api.URL += "&" + pair
+			}
+		case "header":
+			api.Headers[param.Name] = value
+		case "cookie":
+			cookies = append(cookies, param.Name+"="+value)
+		}
+	}
+	if len(cookies) > 0 {
+		api.Headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+}
+
+// parameterValue 按 example > examples > schema 的优先级生成参数值
+func parameterValue(param *v3.Parameter) string {
+	if v, ok := nodeValue(param.Example); ok {
+		return scalarString(v)
+	}
+	if param.Examples != nil {
+		for ex := param.Examples.First(); ex != nil; ex = ex.Next() {
+			if v, ok := nodeValue(ex.Value().Value); ok {
+				return scalarString(v)
+			}
+		}
+	}
+	if param.Schema != nil {
+		return scalarString(schemaExample(param.Schema, 0))
+	}
+	if param.Content != nil {
+		if mt := param.Content.First(); mt != nil && mt.Value().Schema != nil {
+			return scalarString(schemaExample(mt.Value().Schema, 0))
+		}
+	}
+	return "test"
+}
+
+// applyRequestBody 使用 requestBody 中的第一个 content type 生成请求体
+func applyRequestBody(api *API, body *v3.RequestBody) {
+	if body == nil || body.Content == nil {
+		return
+	}
+	pair := body.Content.First()
+	if pair == nil {
+		return
+	}
+	contentType, media := pair.Key(), pair.Value()
+	api.Headers["Content-Type"] = contentType
+
+	var value any
+	if v, ok := nodeValue(media.Example); ok {
+		value = v
+	} else if ex := firstExample(media); ex != nil {
+		value = ex
+	} else if media.Schema != nil {
+		value = schemaExample(media.Schema, 0)
+	}
+	if value == nil {
+		return
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		content, _ := json.Marshal(value)
+		api.Content = string(content)
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"),
+		strings.HasPrefix(contentType, "multipart/form-data"):
+		// multipart 需要 boundary，统一降级为 urlencoded
+		api.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+		api.Content = formEncode(value)
+	default:
+		api.Content = scalarString(value)
+	}
+}
+
+func firstExample(media *v3.MediaType) any {
+	if media.Examples == nil {
+		return nil
+	}
+	for ex := media.Examples.First(); ex != nil; ex = ex.Next() {
+		if v, ok := nodeValue(ex.Value().Value); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// schemaExample 根据 schema 递归生成示例数据，component 中的引用已由 libopenapi 解析
+func schemaExample(proxy *base.SchemaProxy, depth int) any {
+	if proxy == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	schema := proxy.Schema()
+	if schema == nil {
+		return nil
+	}
+
+	if v, ok := nodeValue(schema.Example); ok {
+		return v
+	}
+	for _, ex := range schema.Examples {
+		if v, ok := nodeValue(ex); ok {
+			return v
+		}
+	}
+	if v, ok := nodeValue(schema.Default); ok {
+		return v
+	}
+	if v, ok := nodeValue(schema.Const); ok {
+		return v
+	}
+	if len(schema.Enum) > 0 {
+		if v, ok := nodeValue(schema.Enum[0]); ok {
+			return v
+		}
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]any)
+		for _, sub := range schema.AllOf {
+			if obj, ok := schemaExample(sub, depth+1).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return schemaExample(schema.OneOf[0], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return schemaExample(schema.AnyOf[0], depth+1)
+	}
+
+	var schemaType string
+	for _, t := range schema.Type {
+		// OpenAPI 3.1 中允许 type: [string, "null"]
+		if t != "null" {
+			schemaType = t
+			break
+		}
+	}
+
+	switch {
+	case schemaType == "object" || (schemaType == "" && schema.Properties != nil && schema.Properties.Len() > 0):
+		obj := make(map[string]any)
+		if schema.Properties != nil {
+			for prop := schema.Properties.First(); prop != nil; prop = prop.Next() {
+				obj[prop.Key()] = schemaExample(prop.Value(), depth+1)
+			}
+		}
+		return obj
+	case schemaType == "array":
+		if schema.Items != nil && schema.Items.IsA() {
+			if item := schemaExample(schema.Items.A, depth+1); item != nil {
+				return []any{item}
+			}
+		}
+		return []any{}
+	}
+
+	var literal string
+	if schema.Format != "" {
+		literal = getValueByFormat(schema.Format, true)
+	}
+	// uuid、uri 等未知的格式按类型生成
+	if literal == "" {
+		literal = getValueByType(schemaType, true)
+	}
+	var value any
+	if err := json.Unmarshal([]byte(literal), &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// nodeValue 将 yaml 节点解码为普通的 Go 数据
+func nodeValue(node *yaml.Node) (any, bool) {
+	if node == nil {
+		return nil, false
+	}
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil, false
+	}
+	return normalizeYAML(v), v != nil
+}
+
+// normalizeYAML 把 yaml 解码出的 map[any]any 转换为 json 可以序列化的结构
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return m
+	case map[string]any:
+		for k, val := range t {
+			t[k] = normalizeYAML(val)
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = normalizeYAML(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]any, []any:
+		b, _ := json.Marshal(t)
+		return string(b)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func formEncode(v any) string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return scalarString(v)
+	}
+	values := url.Values{}
+	for k, val := range obj {
+		values.Set(k, scalarString(val))
+	}
+	return values.Encode()
+}
@@ -1,23 +1,165 @@
 package finder
 
 import (
-	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+)
 
-	"github.com/zrquan/gatherer/pkg/util"
+// robots.txt 规则的类型
+const (
+	RobotsAllow    = "allow"
+	RobotsDisallow = "disallow"
 )
 
-var pathRegex = regexp.MustCompile(".*llow: ")
+// Robots 是解析后的 robots.txt
+type Robots struct {
+	Groups   []*RobotsGroup
+	Sitemaps []string
+}
 
-// FindLinksFromRobots 从 robots.txt 文件中获取相对路径
-func FindLinksFromRobots(text string) []string {
-	var endpoints []string
+// RobotsGroup 是一组 User-agent 共用的规则
+type RobotsGroup struct {
+	UserAgents []string
+	Rules      []*RobotsRule
+	CrawlDelay float64 // 秒，没有设置时为 0
+}
+
+// RobotsRule 是一条 Allow 或 Disallow 规则，Path 中可能有通配符 * 和结尾符 $
+type RobotsRule struct {
+	Type string
+	Path string
+}
+
+// ParseRobots 解析 robots.txt，忽略注释和无法识别的指令。连续的 User-agent 行属于同一组，
+// Sitemap 指令不属于任何组
+func ParseRobots(text string) *Robots {
+	robots := &Robots{}
+	var group *RobotsGroup
+	inRules := false // 当前组是否已经出现过规则，之后的 User-agent 开始新的组
 	for _, line := range strings.Split(text, "\n") {
-		if strings.Contains(line, "llow: ") {
-			if path := pathRegex.ReplaceAllString(line, ""); path != "" {
-				endpoints = append(endpoints, util.FilterNewLines(path))
+		line, _, _ = strings.Cut(line, "#")
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if group == nil || inRules {
+				group = &RobotsGroup{}
+				robots.Groups = append(robots.Groups, group)
+				inRules = false
+			}
+			group.UserAgents = append(group.UserAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			if group == nil {
+				continue
+			}
+			inRules = true
+			// 空的 Disallow 表示允许访问所有路径
+			if value != "" {
+				group.Rules = append(group.Rules, &RobotsRule{Type: key, Path: value})
+			}
+		case "crawl-delay":
+			if group == nil {
+				continue
+			}
+			inRules = true
+			if delay, err := strconv.ParseFloat(value, 64); err == nil && delay > 0 {
+				group.CrawlDelay = delay
+			}
+		case "sitemap":
+			if value != "" && !slices.Contains(robots.Sitemaps, value) {
+				robots.Sitemaps = append(robots.Sitemaps, value)
+			}
+		}
+	}
+	return robots
+}
+
+// Group 返回适用于 userAgent 的规则组：优先选择名称最长的匹配组，其次是 * 组，都没有时返回 nil
+func (r *Robots) Group(userAgent string) *RobotsGroup {
+	userAgent = strings.ToLower(userAgent)
+	var matched, wildcard *RobotsGroup
+	longest := 0
+	for _, g := range r.Groups {
+		for _, ua := range g.UserAgents {
+			if ua == "*" {
+				if wildcard == nil {
+					wildcard = g
+				}
+			} else if strings.Contains(userAgent, ua) && len(ua) > longest {
+				matched, longest = g, len(ua)
+			}
+		}
+	}
+	if matched != nil {
+		return matched
+	}
+	return wildcard
+}
+
+// Allowed 判断路径（包括查询字符串）是否允许访问：匹配最长的规则生效，长度相同时 Allow 优先
+func (g *RobotsGroup) Allowed(path string) bool {
+	if g == nil {
+		return true
+	}
+	allowed, longest := true, -1
+	for _, rule := range g.Rules {
+		if !matchRobotsPath(rule.Path, path) {
+			continue
+		}
+		if n := len(rule.Path); n > longest || n == longest && rule.Type == RobotsAllow {
+			allowed, longest = rule.Type == RobotsAllow, n
+		}
+	}
+	return allowed
+}
+
+// Paths 返回所有规则中可以访问的路径前缀：去掉第一个通配符之后的部分和结尾的 $，
+// 只剩下根路径的规则会被忽略
+func (r *Robots) Paths() []string {
+	var paths []string
+	for _, g := range r.Groups {
+		for _, rule := range g.Rules {
+			path := strings.TrimSuffix(rule.Path, "$")
+			path, _, _ = strings.Cut(path, "*")
+			if !strings.HasPrefix(path, "/") || path == "/" || slices.Contains(paths, path) {
+				continue
 			}
+			paths = append(paths, path)
 		}
 	}
-	return endpoints
+	return paths
+}
+
+// matchRobotsPath 按照 RFC 9309 匹配规则，* 匹配任意字符序列，结尾的 $ 匹配路径结尾
+func matchRobotsPath(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// 最后一段在锚定时必须匹配结尾
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// FindLinksFromRobots 从 robots.txt 文件中获取相对路径
+func FindLinksFromRobots(text string) []string {
+	return ParseRobots(text).Paths()
 }
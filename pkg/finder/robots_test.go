@@ -0,0 +1,56 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+const robotsTxt = `# comment
+User-agent: *
+Disallow: /admin/ # private
+Disallow: /*.php$
+Allow: /admin/public
+Crawl-delay: 2
+
+User-agent: Googlebot
+User-agent: gatherer
+Disallow: /search
+Disallow:
+
+Sitemap: https://example.com/sitemap_index.xml
+`
+
+func TestParseRobots(t *testing.T) {
+	robots := ParseRobots(robotsTxt)
+	if len(robots.Groups) != 2 {
+		t.Fatalf("len(groups) should be 2, not %d", len(robots.Groups))
+	}
+	if !slices.Equal(robots.Sitemaps, []string{"https://example.com/sitemap_index.xml"}) {
+		t.Errorf("wrong sitemaps: %v", robots.Sitemaps)
+	}
+	if paths := robots.Paths(); !slices.Equal(paths, []string{"/admin/", "/admin/public", "/search"}) {
+		t.Errorf("wrong paths: %v", paths)
+	}
+
+	wildcard := robots.Group("Mozilla/5.0")
+	if wildcard == nil || wildcard.CrawlDelay != 2 {
+		t.Fatalf("wrong wildcard group: %+v", wildcard)
+	}
+	for path, allowed := range map[string]bool{
+		"/":                  true,
+		"/admin/":            false,
+		"/admin/public/a":    true,
+		"/index.php":         false,
+		"/index.php?id=1":    true,
+		"/search":            true,
+		"/admin/../index.js": false,
+	} {
+		if wildcard.Allowed(path) != allowed {
+			t.Errorf("Allowed(%q) should be %v", path, allowed)
+		}
+	}
+
+	if g := robots.Group("gatherer"); g == nil || g.Allowed("/search?q=1") || !g.Allowed("/admin/") {
+		t.Errorf("wrong gatherer group: %+v", g)
+	}
+}
@@ -0,0 +1,131 @@
+package finder
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// 解压后的 sitemap 最大为 50MB，与 sitemap 协议的限制相同
+const maxSitemapSize = 50 << 20
+
+// Sitemap 是从 sitemap 或 RSS/Atom feed 中解析出的链接
+type Sitemap struct {
+	URLs     []string // 页面链接，包括 hreflang 指向的其他语言版本
+	Sitemaps []string // sitemap index 中的子 sitemap
+}
+
+// ParseSitemap 解析 XML sitemap、sitemap index、文本 sitemap 以及 RSS/Atom feed，
+// 自动解压 gzip，limit 大于 0 时每类链接最多取 limit 个
+func ParseSitemap(data []byte, limit int) (*Sitemap, error) {
+	data, err := gunzip(data)
+	if err != nil {
+		return nil, err
+	}
+	sm := &Sitemap{}
+	add := func(list *[]string, link string) {
+		link = strings.TrimSpace(link)
+		if link != "" && (limit <= 0 || len(*list) < limit) && !slices.Contains(*list, link) {
+			*list = append(*list, link)
+		}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '<' {
+		// 文本 sitemap 每行一个 URL
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); isHTTPURL(line) {
+				add(&sm.URLs, line)
+			}
+		}
+		return sm, nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	decoder.Strict = false
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	var stack []string
+	var root string
+	for {
+		t, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return sm, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if root == "" {
+				root = name
+				if !slices.Contains([]string{"urlset", "sitemapindex", "rss", "feed", "rdf"}, root) {
+					return nil, errors.New("not a sitemap or feed: " + t.Name.Local)
+				}
+			}
+			stack = append(stack, name)
+			// xhtml:link 的 hreflang 版本、Atom 的 link 以及 RSS 的 enclosure
+			switch name {
+			case "link":
+				if href := attr(t, "href"); href != "" {
+					add(&sm.URLs, href)
+				}
+			case "enclosure":
+				add(&sm.URLs, attr(t, "url"))
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) < 2 {
+				continue
+			}
+			text := string(t)
+			switch parent, name := stack[len(stack)-2], stack[len(stack)-1]; {
+			case name == "loc" && parent == "sitemap":
+				add(&sm.Sitemaps, text)
+			case name == "loc" && parent == "url":
+				add(&sm.URLs, text)
+			case name == "link" && (parent == "item" || parent == "channel"):
+				add(&sm.URLs, text)
+			case name == "guid" && parent == "item" && isHTTPURL(strings.TrimSpace(text)):
+				add(&sm.URLs, text)
+			}
+		}
+	}
+	return sm, nil
+}
+
+// isHTTPURL 判断字符串是否为 http 或 https 链接
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// gunzip 解压 gzip 格式的数据，其他数据原样返回
+func gunzip(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, maxSitemapSize))
+}
@@ -0,0 +1,86 @@
+package finder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"slices"
+	"testing"
+)
+
+func TestParseSitemap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		urls     []string
+		sitemaps []string
+	}{
+		{
+			name: "urlset",
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <url>
+    <loc>http://example.com/en/</loc>
+    <xhtml:link rel="alternate" hreflang="de" href="http://example.com/de/"/>
+  </url>
+  <url><loc>http://example.com/a?x=1&amp;y=2</loc></url>
+</urlset>`,
+			urls: []string{"http://example.com/en/", "http://example.com/de/", "http://example.com/a?x=1&y=2"},
+		},
+		{
+			name: "index",
+			data: `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>http://example.com/post-sitemap.xml</loc></sitemap>
+  <sitemap><loc>http://example.com/page-sitemap.xml.gz</loc></sitemap>
+</sitemapindex>`,
+			sitemaps: []string{"http://example.com/post-sitemap.xml", "http://example.com/page-sitemap.xml.gz"},
+		},
+		{
+			name: "rss",
+			data: `<rss version="2.0"><channel><link>http://example.com/</link>
+  <item><link>http://example.com/post/1</link><guid>http://example.com/?p=1</guid><enclosure url="http://example.com/a.mp3"/></item>
+</channel></rss>`,
+			urls: []string{"http://example.com/", "http://example.com/post/1", "http://example.com/?p=1", "http://example.com/a.mp3"},
+		},
+		{
+			name: "atom",
+			data: `<feed xmlns="http://www.w3.org/2005/Atom"><link href="http://example.com/"/>
+  <entry><link rel="alternate" href="http://example.com/entry/1"/><id>urn:uuid:1</id></entry>
+</feed>`,
+			urls: []string{"http://example.com/", "http://example.com/entry/1"},
+		},
+		{
+			name: "text",
+			data: "http://example.com/1\n\nhttps://example.com/2\nnot a url\n",
+			urls: []string{"http://example.com/1", "https://example.com/2"},
+		},
+	}
+	for _, tt := range tests {
+		sm, err := ParseSitemap([]byte(tt.data), 0)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if !slices.Equal(sm.URLs, tt.urls) || !slices.Equal(sm.Sitemaps, tt.sitemaps) {
+			t.Errorf("%s: got %v %v", tt.name, sm.URLs, sm.Sitemaps)
+		}
+	}
+
+	if _, err := ParseSitemap([]byte("<html><body></body></html>"), 0); err == nil {
+		t.Error("HTML should not be parsed as sitemap")
+	}
+}
+
+func TestParseSitemapGzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(`<urlset><url><loc>http://example.com/1</loc></url><url><loc>http://example.com/2</loc></url></urlset>`))
+	w.Close()
+
+	sm, err := ParseSitemap(buf.Bytes(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sm.URLs, []string{"http://example.com/1"}) {
+		t.Errorf("wrong URLs: %v", sm.URLs)
+	}
+}
@@ -0,0 +1,148 @@
+package finder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// 匹配 JS 和 CSS 中的 sourceMappingURL 注释
+var sourceMappingRegex = regexp.MustCompile(`(?m)(?://|/\*)[#@]\s*sourceMappingURL=\s*(\S+?)\s*(?:\*/)?\s*$`)
+
+// SourceMap 是 source map v3 文件的格式，只解析还原源码需要的字段
+type SourceMap struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file"`
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Mappings       string    `json:"mappings"`
+	// 索引格式的 source map 由多个 section 组成
+	Sections []struct {
+		Map *SourceMap `json:"map"`
+	} `json:"sections"`
+}
+
+// SourceFile 是从 source map 中还原的源文件
+type SourceFile struct {
+	Path    string
+	Content string
+}
+
+// FindSourceMapURL 返回 JS 或 CSS 代码中最后一个 sourceMappingURL 注释的值
+func FindSourceMapURL(source string) string {
+	match := sourceMappingRegex.FindAllStringSubmatch(source, -1)
+	if len(match) == 0 {
+		return ""
+	}
+	return match[len(match)-1][1]
+}
+
+// IsInlineSourceMap 判断 sourceMappingURL 是否为内联的 data URI
+func IsInlineSourceMap(link string) bool {
+	return strings.HasPrefix(link, "data:")
+}
+
+// DecodeInlineSourceMap 解码内联在 data URI 中的 source map
+func DecodeInlineSourceMap(link string) ([]byte, error) {
+	meta, data, ok := strings.Cut(strings.TrimPrefix(link, "data:"), ",")
+	if !ok {
+		return nil, errors.New("invalid data URI")
+	}
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	decoded, err := url.PathUnescape(data)
+	return []byte(decoded), err
+}
+
+// ParseSourceMap 解析 source map 文件
+func ParseSourceMap(data []byte) (*SourceMap, error) {
+	// 部分 source map 以 )]}' 开头防止 XSSI
+	if i := strings.IndexByte(string(data[:min(len(data), 16)]), '{'); i > 0 {
+		data = data[i:]
+	}
+	var sm SourceMap
+	if err := json.Unmarshal(data, &sm); err != nil {
+		return nil, err
+	}
+	if len(sm.Sources) == 0 && len(sm.Sections) == 0 {
+		return nil, errors.New("not a source map")
+	}
+	return &sm, nil
+}
+
+// Files 返回 source map 中包含源码的文件，路径已经去掉 webpack:// 等前缀并规范化为相对路径
+func (sm *SourceMap) Files() []*SourceFile {
+	var files []*SourceFile
+	for _, section := range sm.Sections {
+		if section.Map != nil {
+			files = append(files, section.Map.Files()...)
+		}
+	}
+	for i, source := range sm.Sources {
+		if i >= len(sm.SourcesContent) || sm.SourcesContent[i] == nil {
+			continue
+		}
+		files = append(files, &SourceFile{
+			Path:    cleanSourcePath(sm.SourceRoot, source),
+			Content: *sm.SourcesContent[i],
+		})
+	}
+	return files
+}
+
+// FindLinksFromSourceMap 从 source map 还原的源码中获取 URL
+func FindLinksFromSourceMap(sm *SourceMap) []string {
+	var endpoints []string
+	for _, f := range sm.Files() {
+		// 第三方依赖中的链接与目标无关
+		if strings.Contains(f.Path, "node_modules/") {
+			continue
+		}
+		for _, ep := range FindLinksFromJS(f.Content) {
+			if !slices.Contains(endpoints, ep) {
+				endpoints = append(endpoints, ep)
+			}
+		}
+	}
+	return endpoints
+}
+
+// DumpSourceFiles 将还原的源文件写入 dir，文件不会写到 dir 之外
+func DumpSourceFiles(dir string, files []*SourceFile) error {
+	for _, f := range files {
+		name := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, []byte(f.Content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanSourcePath 去掉 webpack://、file:// 等前缀和 ../，返回规范化的相对路径
+func cleanSourcePath(root, source string) string {
+	if root != "" && !strings.Contains(source, "://") {
+		source = strings.TrimSuffix(root, "/") + "/" + source
+	}
+	if _, rest, ok := strings.Cut(source, "://"); ok {
+		source = rest
+	}
+	source, _, _ = strings.Cut(source, "?")
+	// path.Clean 会保留开头的 ..，加上 / 后可以全部去掉
+	p := strings.TrimPrefix(path.Clean("/"+source), "/")
+	if p == "" {
+		p = "unknown"
+	}
+	return p
+}
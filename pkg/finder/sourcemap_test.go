@@ -0,0 +1,49 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindSourceMapURL(t *testing.T) {
+	tests := map[string]string{
+		"var a=1;\n//# sourceMappingURL=app.js.map":           "app.js.map",
+		"var a=1;\n//@ sourceMappingURL=/maps/app.js.map\n":   "/maps/app.js.map",
+		"a{color:red}\n/*# sourceMappingURL=style.css.map */": "style.css.map",
+		"var a=1;": "",
+	}
+	for source, want := range tests {
+		if got := FindSourceMapURL(source); got != want {
+			t.Errorf("FindSourceMapURL(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestFindLinksFromSourceMap(t *testing.T) {
+	data := `{"version":3,"sources":["webpack:///./src/api.js","webpack:///../../etc/passwd","webpack:///./node_modules/axios/index.js"],` +
+		`"sourcesContent":["export const getUser = id => fetch(\"/api/v1/users/\" + id)","root","var u = \"/vendor/api/path\""],"mappings":"AAAA"}`
+	sm, err := ParseSourceMap([]byte(")]}'\n" + data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := FindLinksFromSourceMap(sm)
+	if len(links) != 1 || links[0] != "/api/v1/users/" {
+		t.Errorf("unexpected links: %v", links)
+	}
+
+	dir := t.TempDir()
+	if err := DumpSourceFiles(dir, sm.Files()); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"src/api.js", "etc/passwd", "node_modules/axios/index.js"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("source file %s not dumped: %s", name, err)
+		}
+	}
+
+	inline, err := DecodeInlineSourceMap("data:application/json;charset=utf-8;base64,eyJ2ZXJzaW9uIjozfQ==")
+	if err != nil || string(inline) != `{"version":3}` {
+		t.Errorf("DecodeInlineSourceMap() = %q, %v", inline, err)
+	}
+}
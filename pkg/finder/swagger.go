@@ -10,6 +10,7 @@ import (
 	base "github.com/pb33f/libopenapi/datamodel/high/base"
 	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/pb33f/libopenapi/utils"
 )
 
 type API struct {
@@ -25,6 +26,7 @@ type Param struct {
 	in    string
 }
 
+// FindLinksFromSwagger 解析 Swagger 2.0 或 OpenAPI 3.x 文档，生成可直接重放的 API 请求
 func FindLinksFromSwagger(source []byte) ([]*API, error) {
 	doc, err := libopenapi.NewDocument(source)
 
@@ -32,6 +34,13 @@ func FindLinksFromSwagger(source []byte) ([]*API, error) {
 		return nil, fmt.Errorf("cannot create new document: %e", err)
 	}
 
+	if info := doc.GetSpecInfo(); info != nil && info.SpecType != utils.OpenApi2 {
+		return findLinksFromOpenAPI(doc)
+	}
+	return findLinksFromSwaggerV2(doc)
+}
+
+func findLinksFromSwaggerV2(doc libopenapi.Document) ([]*API, error) {
 	var errs []error
 	var v2Model *libopenapi.DocumentModel[v2.Swagger]
 
@@ -26,3 +26,92 @@ func TestFindLinksFromSwagger(t *testing.T) {
 		fmt.Printf("  > %s\n\n", api.Content)
 	}
 }
+
+func TestFindLinksFromOpenAPI(t *testing.T) {
+	source := []byte(`{
+  "openapi": "3.1.0",
+  "info": {"title": "test", "version": "1.0"},
+  "servers": [{"url": "/{base}", "variables": {"base": {"default": "api"}}}],
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "parameters": [
+          {"name": "fields", "in": "query", "schema": {"type": "string", "example": "name"}},
+          {"name": "X-Trace", "in": "header", "schema": {"type": "string"}},
+          {"name": "session", "in": "cookie", "schema": {"type": "string"}}
+        ]
+      },
+      "put": {
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "properties": {
+          "name": {"type": ["string", "null"]},
+          "email": {"type": "string", "format": "email"},
+          "age": {"type": "integer"}
+        }
+      }
+    }
+  }
+}`)
+
+	endpoints, err := FindLinksFromSwagger(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("len(endpoints) should be 2, not %d", len(endpoints))
+	}
+
+	get := endpoints[0]
+	if get.Method != "get" || get.URL != "/api/users/1?fields=name" {
+		t.Errorf("wrong API: [%s] %s", get.Method, get.URL)
+	}
+	if get.Headers["X-Trace"] != "test" || get.Headers["Cookie"] != "session=test" {
+		t.Errorf("wrong headers: %v", get.Headers)
+	}
+
+	put := endpoints[1]
+	if put.Headers["Content-Type"] != "application/json" {
+		t.Errorf("wrong content type: %s", put.Headers["Content-Type"])
+	}
+	if put.Content != `{"age":1,"email":"gatherer@1234.com","name":"test"}` {
+		t.Errorf("wrong content: %s", put.Content)
+	}
+}
+
+func TestFindLinksFromOpenAPIUnknownFormat(t *testing.T) {
+	source := []byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1.0"},
+  "paths": {
+    "/users/{id}": {
+      "post": {
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}}],
+        "requestBody": {
+          "content": {"application/json": {"schema": {"type": "object", "properties": {"site": {"type": "string", "format": "uri"}}}}}
+        }
+      }
+    }
+  }
+}`)
+
+	endpoints, err := FindLinksFromSwagger(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("len(endpoints) should be 1, not %d", len(endpoints))
+	}
+	if ep := endpoints[0]; ep.URL != "/users/test" || ep.Content != `{"site":"test"}` {
+		t.Errorf("wrong API: %s %s", ep.URL, ep.Content)
+	}
+}
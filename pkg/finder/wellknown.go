@@ -0,0 +1,227 @@
+package finder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// WellKnownFile 是每个主机都会尝试访问的元数据文件，Parse 为 nil 的文件由 JS 的处理流程解析
+type WellKnownFile struct {
+	Path  string
+	Parse func(data []byte) ([]string, error)
+}
+
+// WellKnownFiles 是 .well-known 目录和网站根目录下常见的元数据文件
+var WellKnownFiles = []*WellKnownFile{
+	{Path: "/.well-known/security.txt", Parse: parseSecurityTxt},
+	{Path: "/security.txt", Parse: parseSecurityTxt},
+	{Path: "/.well-known/openid-configuration", Parse: parseJSONLinks},
+	{Path: "/.well-known/oauth-authorization-server", Parse: parseJSONLinks},
+	{Path: "/.well-known/assetlinks.json", Parse: parseJSONLinks},
+	{Path: "/.well-known/apple-app-site-association", Parse: parseAppleAppSite},
+	{Path: "/apple-app-site-association", Parse: parseAppleAppSite},
+	{Path: "/crossdomain.xml", Parse: parseCrossDomain},
+	{Path: "/clientaccesspolicy.xml", Parse: parseClientAccessPolicy},
+	{Path: "/manifest.json", Parse: parseManifest},
+	{Path: "/site.webmanifest", Parse: parseManifest},
+	{Path: "/humans.txt", Parse: parseHumansTxt},
+	{Path: "/sw.js"},
+	{Path: "/service-worker.js"},
+	{Path: "/firebase-messaging-sw.js"},
+}
+
+var textURLRegex = regexp.MustCompile(`https?://[^\s"'<>()]+`)
+
+// FindLinksFromWellKnown 按照文件格式获取链接，CSP 风格的主机（如 crossdomain.xml 中的域名）以 //host/ 形式返回
+func FindLinksFromWellKnown(path string, data []byte) ([]string, error) {
+	i := slices.IndexFunc(WellKnownFiles, func(f *WellKnownFile) bool { return f.Path == path })
+	if i < 0 || WellKnownFiles[i].Parse == nil {
+		return nil, nil
+	}
+	return WellKnownFiles[i].Parse(data)
+}
+
+// parseSecurityTxt 获取 security.txt 中 Contact、Policy、Hiring 等字段的链接，忽略邮箱和电话
+func parseSecurityTxt(data []byte) ([]string, error) {
+	var links []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, value, found := strings.Cut(line, ":"); found {
+			if value = strings.TrimSpace(value); isHTTPURL(value) && !slices.Contains(links, value) {
+				links = append(links, value)
+			}
+		}
+	}
+	return links, scanner.Err()
+}
+
+// parseJSONLinks 获取 JSON 中所有 http 或 https 链接，适用于 openid-configuration、assetlinks.json 等文件
+func parseJSONLinks(data []byte) ([]string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var links []string
+	walkJSON(v, func(key, value string) {
+		if isHTTPURL(value) && !slices.Contains(links, value) {
+			links = append(links, value)
+		}
+	})
+	return links, nil
+}
+
+// parseAppleAppSite 获取 apple-app-site-association 中 paths 和 components 的路径前缀
+func parseAppleAppSite(data []byte) ([]string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var links []string
+	var walk func(v any, inPaths bool)
+	walk = func(v any, inPaths bool) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				walk(value, key == "paths" || key == "/")
+			}
+		case []any:
+			for _, item := range v {
+				walk(item, inPaths)
+			}
+		case string:
+			if inPaths {
+				path := strings.TrimSpace(strings.TrimPrefix(v, "NOT "))
+				path, _, _ = strings.Cut(path, "*")
+				path, _, _ = strings.Cut(path, "?")
+				if strings.HasPrefix(path, "/") && path != "/" && !slices.Contains(links, path) {
+					links = append(links, path)
+				}
+			}
+		}
+	}
+	walk(v, false)
+	slices.Sort(links)
+	return links, nil
+}
+
+// parseCrossDomain 获取 Flash crossdomain.xml 允许访问的域名
+func parseCrossDomain(data []byte) ([]string, error) {
+	var links []string
+	err := walkXML(data, func(e xml.StartElement) {
+		switch strings.ToLower(e.Name.Local) {
+		case "allow-access-from", "allow-http-request-headers-from":
+			if host := sourceHost(attr(e, "domain")); host != "" && !slices.Contains(links, host) {
+				links = append(links, host)
+			}
+		}
+	})
+	return links, err
+}
+
+// parseClientAccessPolicy 获取 Silverlight clientaccesspolicy.xml 允许访问的域名和开放的路径
+func parseClientAccessPolicy(data []byte) ([]string, error) {
+	var links []string
+	err := walkXML(data, func(e xml.StartElement) {
+		var link string
+		switch strings.ToLower(e.Name.Local) {
+		case "domain":
+			link = sourceHost(attr(e, "uri"))
+		case "resource":
+			if path := attr(e, "path"); path != "/" {
+				link = path
+			}
+		}
+		if link != "" && !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	})
+	return links, err
+}
+
+// parseManifest 获取 Web App Manifest 中的入口、作用域、图标、快捷方式等链接，相对链接相对于 manifest 文件
+func parseManifest(data []byte) ([]string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var links []string
+	walkJSON(v, func(key, value string) {
+		switch key {
+		case "start_url", "scope", "src", "url", "id":
+		default:
+			if !isHTTPURL(value) {
+				return
+			}
+		}
+		if isLinkValue(value) && !slices.Contains(links, value) {
+			links = append(links, value)
+		}
+	})
+	return links, nil
+}
+
+// parseHumansTxt 获取 humans.txt 中的链接
+func parseHumansTxt(data []byte) ([]string, error) {
+	var links []string
+	for _, link := range textURLRegex.FindAllString(string(data), -1) {
+		link = strings.TrimRight(link, ".,;")
+		if !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// walkJSON 按键名顺序遍历 JSON 中的字符串，key 为字符串所在的字段名，数组元素使用数组的字段名
+func walkJSON(v any, fn func(key, value string)) {
+	var walk func(key string, v any)
+	walk = func(key string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				walk(k, v[k])
+			}
+		case []any:
+			for _, item := range v {
+				walk(key, item)
+			}
+		case string:
+			fn(key, strings.TrimSpace(v))
+		}
+	}
+	walk("", v)
+}
+
+// walkXML 遍历 XML 中的每个开始标签
+func walkXML(data []byte, fn func(e xml.StartElement)) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		t, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if e, ok := t.(xml.StartElement); ok {
+			fn(e)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindLinksFromWellKnown(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want []string
+	}{
+		{
+			path: "/.well-known/security.txt",
+			data: "# comment\nContact: mailto:security@example.com\nContact: https://example.com/security\nPolicy: https://example.com/policy\nExpires: 2030-01-01T00:00:00.000Z\n",
+			want: []string{"https://example.com/security", "https://example.com/policy"},
+		},
+		{
+			path: "/.well-known/openid-configuration",
+			data: `{"issuer":"https://sso.example.com","token_endpoint":"https://sso.example.com/token","scopes_supported":["openid"]}`,
+			want: []string{"https://sso.example.com", "https://sso.example.com/token"},
+		},
+		{
+			path: "/.well-known/apple-app-site-association",
+			data: `{"applinks":{"details":[{"appID":"ABC.com.example","paths":["/buy/*","NOT /buy/secret/*","*"]},{"components":[{"/":"/help/*?","comment":"help"}]}]}}`,
+			want: []string{"/buy/", "/buy/secret/", "/help/"},
+		},
+		{
+			path: "/crossdomain.xml",
+			data: `<cross-domain-policy><allow-access-from domain="*.example.com"/><allow-access-from domain="*"/></cross-domain-policy>`,
+			want: []string{"//example.com/"},
+		},
+		{
+			path: "/clientaccesspolicy.xml",
+			data: `<access-policy><cross-domain-access><policy><allow-from><domain uri="https://partner.com"/></allow-from><grant-to><resource path="/api/" include-subpaths="true"/></grant-to></policy></cross-domain-access></access-policy>`,
+			want: []string{"https://partner.com/", "/api/"},
+		},
+		{
+			path: "/manifest.json",
+			data: `{"name":"App","start_url":"./?utm_source=pwa","icons":[{"src":"icons/192.png"}],"related_applications":[{"url":"https://play.google.com/store/apps/details?id=x"}]}`,
+			want: []string{"icons/192.png", "https://play.google.com/store/apps/details?id=x", "./?utm_source=pwa"},
+		},
+		{
+			path: "/humans.txt",
+			data: "/* TEAM */\nSite: https://example.com/team.\nTwitter: @example",
+			want: []string{"https://example.com/team"},
+		},
+	}
+	for _, tt := range tests {
+		links, err := FindLinksFromWellKnown(tt.path, []byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: %s", tt.path, err)
+			continue
+		}
+		if !slices.Equal(links, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.path, links, tt.want)
+		}
+	}
+}
@@ -34,6 +34,16 @@ func (w *Wordlist) Value() []byte {
 	return w.data[w.position]
 }
 
+// Position returns the current cursor position, -1 if Next has not been called yet
+func (w *Wordlist) Position() int {
+	return w.position
+}
+
+// Seek moves the cursor to the given position, the next call of Next will move to the word after it
+func (w *Wordlist) Seek(position int) {
+	w.position = position
+}
+
 // Total returns the size of wordlist
 func (w *Wordlist) Total() int {
 	return len(w.data)
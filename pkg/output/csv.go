@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var csvHeader = []string{"url", "method", "status", "length", "title", "content_type", "source_url", "source", "finder", "raw", "depth", "target", "error", "skipped", "confidence", "type", "location"}
+
+// CSVWriter 以 CSV 格式输出结果，首次写入时输出表头
+type CSVWriter struct {
+	mutex       sync.Mutex
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func NewCSVWriter(w io.Writer) IWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *CSVWriter) Write(result *Result) error {
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+
+	if !cw.wroteHeader {
+		if err := cw.w.Write(csvHeader); err != nil {
+			return err
+		}
+		cw.wroteHeader = true
+	}
+	record := []string{
+		result.URL,
+		result.Method,
+		strconv.Itoa(result.Status),
+		strconv.Itoa(result.Length),
+		result.Title,
+		result.ContentType,
+		result.SourceURL,
+		string(result.Source),
+		result.Finder,
+		result.Raw,
+		strconv.Itoa(result.Depth),
+		result.Target,
+		result.Error,
+		result.Skipped,
+		result.Confidence,
+		result.Type,
+		"",
+	}
+	if s := result.Secret; s != nil {
+		record[8], record[9] = s.Rule, s.Match
+		record[len(record)-1] = fmt.Sprintf("%d:%d", s.Line, s.Column)
+	}
+	if f := result.Form; f != nil {
+		record[9] = strings.Join(f.FieldNames(), ",")
+	}
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+	// 每条记录都立即刷新，避免总超时退出时丢失数据
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *CSVWriter) Close() error {
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+	cw.w.Flush()
+	return cw.w.Error()
+}
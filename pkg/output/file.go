@@ -0,0 +1,33 @@
+package output
+
+import (
+	"os"
+)
+
+// FileWriter 将指定格式的结果写入文件
+type FileWriter struct {
+	IWriter
+	file *os.File
+}
+
+func NewFileWriter(path, format string) (IWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWriterByName(format, file)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return &FileWriter{IWriter: w, file: file}, nil
+}
+
+func (fw *FileWriter) Close() error {
+	if err := fw.IWriter.Close(); err != nil {
+		fw.file.Close()
+		return err
+	}
+	return fw.file.Close()
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+type GraphNode struct {
+	URL    string `json:"url"`
+	Method string `json:"method,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Depth  int    `json:"depth"`
+}
+
+type GraphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Source     Source `json:"source"`
+	Finder     string `json:"finder,omitempty"`
+	Raw        string `json:"raw,omitempty"`
+	Confidence string `json:"confidence,omitempty"`
+}
+
+// GraphWriter 收集所有结果的来源关系，在关闭时以 DOT 或 JSON 格式输出整个爬取过程的发现图
+type GraphWriter struct {
+	mutex  sync.Mutex
+	w      io.Writer
+	format string
+	nodes  []*GraphNode
+	index  map[string]*GraphNode
+	edges  []*GraphEdge
+}
+
+func NewGraphWriter(w io.Writer, format string) IWriter {
+	return &GraphWriter{
+		w:      w,
+		format: format,
+		index:  make(map[string]*GraphNode),
+	}
+}
+
+func (gw *GraphWriter) Write(result *Result) error {
+	// 发现图只包含链接，不包含敏感信息、表单等其他类型的结果
+	if result.Type != "" {
+		return nil
+	}
+
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	node := gw.node(result.URL)
+	if node.Status == 0 {
+		node.Method = result.Method
+		node.Status = result.Status
+		node.Title = result.Title
+		node.Depth = result.Depth
+	}
+
+	if result.SourceURL != "" {
+		gw.node(result.SourceURL)
+		gw.edges = append(gw.edges, &GraphEdge{
+			From:       result.SourceURL,
+			To:         result.URL,
+			Source:     result.Source,
+			Finder:     result.Finder,
+			Raw:        result.Raw,
+			Confidence: result.Confidence,
+		})
+	}
+	return nil
+}
+
+// node 返回 URL 对应的节点，不存在时创建（父页面可能因为过滤而没有结果）
+func (gw *GraphWriter) node(url string) *GraphNode {
+	if n, ok := gw.index[url]; ok {
+		return n
+	}
+	n := &GraphNode{URL: url}
+	gw.index[url] = n
+	gw.nodes = append(gw.nodes, n)
+	return n
+}
+
+func (gw *GraphWriter) Close() error {
+	gw.mutex.Lock()
+	defer gw.mutex.Unlock()
+
+	if gw.format == "dot" {
+		return gw.writeDOT()
+	}
+	encoder := json.NewEncoder(gw.w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Nodes []*GraphNode `json:"nodes"`
+		Edges []*GraphEdge `json:"edges"`
+	}{gw.nodes, gw.edges})
+}
+
+func (gw *GraphWriter) writeDOT() error {
+	var b strings.Builder
+	b.WriteString("digraph gatherer {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range gw.nodes {
+		label := n.URL
+		if n.Status != 0 {
+			label += fmt.Sprintf("\n%d", n.Status)
+		}
+		if n.Title != "" {
+			label += " " + n.Title
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(n.URL), dotQuote(label))
+	}
+	for _, e := range gw.edges {
+		label := e.Finder
+		if label == "" {
+			label = string(e.Source)
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(label))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(gw.w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
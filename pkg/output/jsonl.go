@@ -0,0 +1,29 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONLWriter 每行输出一个 JSON 对象（JSON Lines）
+type JSONLWriter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewJSONLWriter(w io.Writer) IWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &JSONLWriter{encoder: encoder}
+}
+
+func (jw *JSONLWriter) Write(result *Result) error {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	return jw.encoder.Encode(result)
+}
+
+func (jw *JSONLWriter) Close() error {
+	return nil
+}
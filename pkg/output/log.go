@@ -1,7 +1,9 @@
 package output
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -21,3 +23,63 @@ func SetFormatter(json bool) {
 		})
 	}
 }
+
+// LogWriter 通过 logrus 在终端打印结果
+type LogWriter struct{}
+
+func NewLogWriter() IWriter {
+	return &LogWriter{}
+}
+
+func (lw *LogWriter) Write(result *Result) error {
+	if s := result.Secret; s != nil {
+		log.WithFields(log.Fields{
+			"rule":     s.Rule,
+			"severity": s.Severity,
+			"location": fmt.Sprintf("%s:%d:%d", result.URL, s.Line, s.Column),
+		}).Warn("Found secret: ", s.Match)
+		return nil
+	}
+	if f := result.Form; f != nil {
+		log.WithFields(log.Fields{"method": f.Method, "fields": strings.Join(f.FieldNames(), ",")}).Info("Found form: ", result.URL)
+		return nil
+	}
+	if g := result.GraphQL; g != nil {
+		log.WithFields(log.Fields{"operation": g.Operation, "url": result.URL}).Info("Found GraphQL operation: ", g.Name)
+		return nil
+	}
+	if w := result.Wildcard; w != nil {
+		log.WithFields(log.Fields{
+			"code":     w.Status,
+			"length":   [2]int{w.MinLength, w.MaxLength},
+			"words":    [2]int{w.MinWords, w.MaxWords},
+			"title":    w.Title,
+			"location": w.Location,
+		}).Warn("Wildcard responses detected: ", result.URL)
+		return nil
+	}
+	if result.Type == TypeAsset {
+		log.WithFields(log.Fields{"finder": result.Finder, "source": result.SourceURL}).Info("Found asset: ", result.URL)
+		return nil
+	}
+	if result.Skipped != "" {
+		log.WithFields(log.Fields{"method": result.Method, "reason": result.Skipped}).Warn("Skip request: ", result.URL)
+		return nil
+	}
+
+	fields := log.Fields{"code": result.Status, "length": result.Length}
+	if result.Title != "" {
+		fields["title"] = result.Title
+	}
+
+	if result.Error != "" {
+		log.WithFields(fields).Warn(result.URL)
+	} else {
+		log.WithFields(fields).Info(result.URL)
+	}
+	return nil
+}
+
+func (lw *LogWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,229 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Source 表示链接是通过什么方式发现的
+type Source string
+
+const (
+	SourceTarget    Source = "target"
+	SourceHTML      Source = "html"
+	SourceJS        Source = "js"
+	SourceSwagger   Source = "swagger"
+	SourceRobots    Source = "robots"
+	SourceSitemap   Source = "sitemap"
+	SourceWordlist  Source = "wordlist"
+	SourceRedirect  Source = "redirect"
+	SourceHeader    Source = "header"
+	SourceWellKnown Source = "well-known"
+	SourceGraphQL   Source = "graphql"
+	SourceBrowser   Source = "browser"
+	SourceSourceMap Source = "sourcemap"
+)
+
+// 提取链接的具体 finder
+const (
+	FinderTarget     = "target"
+	FinderWordlist   = "wordlist"
+	FinderAHref      = "a[href]"
+	FinderScriptSrc  = "script[src]"
+	FinderFormAction = "form[action]"
+	FinderFormSubmit = "form submit"
+	FinderTitle      = "title"
+	FinderLinkHref   = "link[href]"
+	FinderIframeSrc  = "iframe[src]"
+	FinderAreaHref   = "area[href]"
+	FinderBaseHref   = "base[href]"
+	FinderMeta       = "meta refresh"
+	FinderSrcset     = "srcset"
+	FinderInline     = "inline script"
+	FinderEvent      = "event handler"
+	FinderDataAttr   = "data-*"
+	FinderLinkRegex  = "linkFinderRegex"
+	FinderWebpack    = "webpack chunk"
+	FinderVite       = "vite chunk"
+	FinderImport     = "dynamic import"
+	FinderImportMap  = "import map"
+	FinderRobots     = "robots"
+	FinderRobotsMap  = "robots sitemap"
+	FinderSitemap    = "sitemap"
+	FinderSitemapIdx = "sitemap index"
+	FinderWellKnown  = "well-known"
+	FinderGraphQL    = "graphql"
+	FinderIntrospect = "graphql introspection"
+	FinderSwagger    = "swagger"
+	FinderAPIDoc     = "api-doc probe"
+	FinderSwaggerRes = "swagger-resources"
+	FinderSwaggerCfg = "swagger-config"
+	FinderSwaggerUI  = "swagger-ui config"
+	FinderLocation   = "location"
+	FinderLinkHeader = "link"
+	FinderContentLoc = "content-location"
+	FinderRefresh    = "refresh"
+	FinderCSP        = "content-security-policy"
+	FinderCORS       = "access-control-allow-origin"
+	FinderWebSocket  = "websocket"
+	FinderRoute      = "route"
+	FinderSourceMap  = "sourceMappingURL"
+	FinderMapProbe   = "sourcemap probe"
+	FinderFetch      = "fetch"
+	FinderAxios      = "axios"
+	FinderJQuery     = "jQuery.ajax"
+	FinderXHR        = "XMLHttpRequest.open"
+	FinderJSExpr     = "js expression"
+)
+
+// 结果类型，请求的结果类型为空
+const (
+	TypeSecret   = "secret"
+	TypeForm     = "form"
+	TypeAsset    = "asset" // 作用域外的主机
+	TypeGraphQL  = "graphql"
+	TypeWildcard = "wildcard" // 字典模式下学习到的通配响应基线
+)
+
+// Provenance 记录链接的发现方式：来源类别、提取它的 finder 以及匹配到的原始字符串
+type Provenance struct {
+	Source     Source
+	Finder     string
+	Raw        string
+	Confidence string // finder 对结果的可信度，只有部分 finder 提供
+}
+
+// Result 是一次请求的结构化结果
+type Result struct {
+	URL         string    `json:"url"`
+	Method      string    `json:"method"`
+	Status      int       `json:"status"`
+	Length      int       `json:"length"`
+	Title       string    `json:"title,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	SourceURL   string    `json:"source_url,omitempty"`
+	Source      Source    `json:"source"`
+	Finder      string    `json:"finder,omitempty"`
+	Raw         string    `json:"raw,omitempty"`
+	Depth       int       `json:"depth"`
+	Target      string    `json:"target,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Skipped     string    `json:"skipped,omitempty"` // 请求未发送的原因
+	Confidence  string    `json:"confidence,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Secret      *Secret   `json:"secret,omitempty"`
+	Form        *Form     `json:"form,omitempty"`
+	GraphQL     *GraphQL  `json:"graphql,omitempty"`
+	Wildcard    *Wildcard `json:"wildcard,omitempty"`
+}
+
+// Secret 是在响应内容中发现的敏感信息，Result.URL 为其所在的位置
+type Secret struct {
+	Rule        string  `json:"rule"`
+	Description string  `json:"description,omitempty"`
+	Severity    string  `json:"severity"`
+	Match       string  `json:"match"`
+	Line        int     `json:"line"`
+	Column      int     `json:"column"`
+	Offset      int     `json:"offset"`
+	Entropy     float64 `json:"entropy"`
+}
+
+// Form 是在页面中发现的表单，Result.URL 为表单提交的地址
+type Form struct {
+	Method  string       `json:"method"`
+	Action  string       `json:"action"`
+	Enctype string       `json:"enctype"`
+	Fields  []*FormField `json:"fields"`
+}
+
+type FormField struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Value    string   `json:"value,omitempty"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	CSRF     bool     `json:"csrf,omitempty"`
+}
+
+// FieldNames 返回所有字段名
+func (f *Form) FieldNames() []string {
+	names := make([]string, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// GraphQL 是通过内省查询或 JS 代码发现的 GraphQL 操作，Result.URL 为接口地址或 JS 文件
+type GraphQL struct {
+	Operation  string   `json:"operation"`
+	Name       string   `json:"name"`
+	Args       []string `json:"args,omitempty"`
+	ReturnType string   `json:"return_type,omitempty"`
+	Document   string   `json:"document,omitempty"`
+}
+
+// Wildcard 是字典模式下某个目录和扩展名的通配响应基线，Result.URL 为 key，如 http://example.com/admin/*.php
+type Wildcard struct {
+	Status    int    `json:"status"`
+	MinLength int    `json:"min_length"`
+	MaxLength int    `json:"max_length"`
+	MinWords  int    `json:"min_words"`
+	MaxWords  int    `json:"max_words"`
+	Title     string `json:"title,omitempty"`
+	Location  string `json:"location,omitempty"`
+}
+
+type IWriter interface {
+	Write(result *Result) error
+	Close() error
+}
+
+func NewWriterByName(name string, w io.Writer) (IWriter, error) {
+	switch name {
+	case "text":
+		return NewTextWriter(w), nil
+	case "jsonl":
+		return NewJSONLWriter(w), nil
+	case "csv":
+		return NewCSVWriter(w), nil
+	case "dot", "graph":
+		return NewGraphWriter(w, name), nil
+	default:
+		return nil, fmt.Errorf("could not create writer with name %s", name)
+	}
+}
+
+// MultiWriter 将结果同时写入多个 IWriter
+type MultiWriter struct {
+	writers []IWriter
+}
+
+func NewMultiWriter(writers ...IWriter) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+func (mw *MultiWriter) Add(w IWriter) {
+	mw.writers = append(mw.writers, w)
+}
+
+func (mw *MultiWriter) Write(result *Result) error {
+	var firstErr error
+	for _, w := range mw.writers {
+		if err := w.Write(result); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (mw *MultiWriter) Close() error {
+	var firstErr error
+	for _, w := range mw.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf)
+	w.Write(&Result{URL: "http://example.com/a?x=1&y=2", Method: "GET", Status: 200, Source: SourceHTML, Depth: 2})
+
+	expected := `{"url":"http://example.com/a?x=1&y=2","method":"GET","status":200,"length":0,"source":"html","depth":2}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("wrong JSON line: %s", buf.String())
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	w.Write(&Result{URL: "http://example.com/", Method: "GET", Status: 200, Length: 10, Title: "a, b", Source: SourceTarget})
+	w.Write(&Result{URL: "http://example.com/x", Method: "GET", Status: 500, Source: SourceJS, SourceURL: "http://example.com/"})
+	w.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) should be 3, not %d", len(lines))
+	}
+	if lines[1] != `http://example.com/,GET,200,10,"a, b",,,target,,,0,,,,,,` {
+		t.Errorf("wrong CSV record: %s", lines[1])
+	}
+}
+
+func TestTextWriterSecret(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewTextWriter(&buf)
+	w.Write(&Result{
+		URL:    "http://example.com/app.js",
+		Type:   TypeSecret,
+		Secret: &Secret{Rule: "jwt", Severity: "medium", Match: "eyJ...", Line: 3, Column: 7},
+	})
+
+	if buf.String() != "[SECRET] [medium] [jwt] http://example.com/app.js:3:7 eyJ...\n" {
+		t.Errorf("wrong text line: %s", buf.String())
+	}
+}
+
+func TestNewWriterByName(t *testing.T) {
+	if _, err := NewWriterByName("xml", &bytes.Buffer{}); err == nil {
+		t.Error("unknown writer name should return an error")
+	}
+}
+
+func TestGraphWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewGraphWriter(&buf, "dot")
+	w.Write(&Result{URL: "http://example.com/", Method: "GET", Status: 200, Source: SourceTarget})
+	w.Write(&Result{
+		URL:       "http://example.com/app.js",
+		Method:    "GET",
+		Status:    200,
+		SourceURL: "http://example.com/",
+		Source:    SourceHTML,
+		Finder:    FinderScriptSrc,
+		Raw:       "/app.js",
+	})
+	w.Close()
+
+	if !strings.Contains(buf.String(), `"http://example.com/" -> "http://example.com/app.js" [label="script[src]"];`) {
+		t.Errorf("missing edge in DOT graph:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// TextWriter 以纯文本格式逐行输出结果，适合写入文件
+type TextWriter struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func NewTextWriter(w io.Writer) IWriter {
+	return &TextWriter{w: w}
+}
+
+func (tw *TextWriter) Write(result *Result) error {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if s := result.Secret; s != nil {
+		_, err := fmt.Fprintf(tw.w, "[SECRET] [%s] [%s] %s:%d:%d %s\n", s.Severity, s.Rule, result.URL, s.Line, s.Column, s.Match)
+		return err
+	}
+
+	if f := result.Form; f != nil {
+		_, err := fmt.Fprintf(tw.w, "[FORM] [%s] %s (%s)\n", f.Method, result.URL, strings.Join(f.FieldNames(), ", "))
+		return err
+	}
+
+	if g := result.GraphQL; g != nil {
+		line := fmt.Sprintf("[GRAPHQL] [%s] %s %s(%s)", g.Operation, result.URL, g.Name, strings.Join(g.Args, ", "))
+		if g.ReturnType != "" {
+			line += ": " + g.ReturnType
+		}
+		_, err := fmt.Fprintln(tw.w, line)
+		return err
+	}
+	if w := result.Wildcard; w != nil {
+		_, err := fmt.Fprintf(tw.w, "[WILDCARD] [%d] %s length=%d-%d words=%d-%d title=%q location=%s\n",
+			w.Status, result.URL, w.MinLength, w.MaxLength, w.MinWords, w.MaxWords, w.Title, w.Location)
+		return err
+	}
+	if result.Type == TypeAsset {
+		_, err := fmt.Fprintf(tw.w, "[ASSET] %s (%s)\n", result.URL, result.Finder)
+		return err
+	}
+
+	line := fmt.Sprintf("[%d] [%s] [%d] %s", result.Status, result.Method, result.Length, result.URL)
+	if result.Skipped != "" {
+		line = fmt.Sprintf("[SKIP] [%s] %s (%s)", result.Method, result.URL, result.Skipped)
+	}
+	if result.Title != "" {
+		line += fmt.Sprintf(" [%s]", result.Title)
+	}
+	_, err := fmt.Fprintln(tw.w, line)
+	return err
+}
+
+func (tw *TextWriter) Close() error {
+	return nil
+}
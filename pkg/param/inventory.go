@@ -0,0 +1,238 @@
+package param
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// 参数所在的位置
+const (
+	LocationQuery    = "query"    // URL 查询字符串
+	LocationForm     = "form"     // HTML 表单
+	LocationBody     = "body"     // application/x-www-form-urlencoded 请求体
+	LocationJSON     = "json"     // JSON 请求体
+	LocationJS       = "js"       // JS 代码中的客户端调用
+	LocationResponse = "response" // JSON 响应中的字段
+)
+
+// 解析 JSON 字段的最大嵌套深度
+const maxJSONDepth = 3
+
+// Param 是接口的一个参数以及它出现过的位置
+type Param struct {
+	Name      string   `json:"name"`
+	Locations []string `json:"locations"`
+}
+
+// Endpoint 是一个接口（不含查询字符串的 URL）的所有请求方法和参数
+type Endpoint struct {
+	URL     string   `json:"url"`
+	Methods []string `json:"methods"`
+	Params  []*Param `json:"params"`
+}
+
+// Inventory 按接口汇总爬取过程中发现的参数名
+type Inventory struct {
+	mutex     sync.Mutex
+	endpoints map[string]*Endpoint
+	counts    map[string]int // 参数名出现在多少个接口中
+}
+
+func New() *Inventory {
+	return &Inventory{
+		endpoints: make(map[string]*Endpoint),
+		counts:    make(map[string]int),
+	}
+}
+
+// Add 记录接口在某个位置的参数
+func (inv *Inventory) Add(method, link, location string, names ...string) {
+	key := endpointKey(link)
+	if key == "" {
+		return
+	}
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	ep, ok := inv.endpoints[key]
+	if !ok {
+		ep = &Endpoint{URL: key}
+		inv.endpoints[key] = ep
+	}
+	if method = strings.ToUpper(method); method != "" && !slices.Contains(ep.Methods, method) {
+		ep.Methods = append(ep.Methods, method)
+	}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		i := slices.IndexFunc(ep.Params, func(p *Param) bool { return p.Name == name })
+		if i < 0 {
+			ep.Params = append(ep.Params, &Param{Name: name})
+			i = len(ep.Params) - 1
+			inv.counts[name]++
+		}
+		if p := ep.Params[i]; !slices.Contains(p.Locations, location) {
+			p.Locations = append(p.Locations, location)
+		}
+	}
+}
+
+// AddURL 记录 URL 查询字符串中的参数
+func (inv *Inventory) AddURL(method, link string) {
+	u, err := url.Parse(link)
+	if err != nil || u.RawQuery == "" {
+		return
+	}
+	inv.Add(method, link, LocationQuery, QueryParams(u.RawQuery)...)
+}
+
+// AddBody 记录请求体中的参数，支持 JSON 和 application/x-www-form-urlencoded 格式
+func (inv *Inventory) AddBody(method, link string, body []byte) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return
+	}
+	if names, ok := JSONKeys(body); ok {
+		inv.Add(method, link, LocationJSON, names...)
+		return
+	}
+	if !bytes.ContainsAny(body, "\n<{") {
+		inv.Add(method, link, LocationBody, QueryParams(string(body))...)
+	}
+}
+
+// AddResponse 记录 JSON 响应中的字段名，这些字段通常也是接口可以接受的参数
+func (inv *Inventory) AddResponse(method, link string, body []byte) {
+	if names, ok := JSONKeys(body); ok {
+		inv.Add(method, link, LocationResponse, names...)
+	}
+}
+
+// Endpoints 返回所有有参数的接口，按 URL 排序
+func (inv *Inventory) Endpoints() []*Endpoint {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	var endpoints []*Endpoint
+	for _, ep := range inv.endpoints {
+		if len(ep.Params) > 0 {
+			endpoints = append(endpoints, ep)
+		}
+	}
+	slices.SortFunc(endpoints, func(a, b *Endpoint) int { return strings.Compare(a.URL, b.URL) })
+	return endpoints
+}
+
+// Wordlist 返回所有参数名，出现在越多接口中的参数越靠前
+func (inv *Inventory) Wordlist() []string {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	names := make([]string, 0, len(inv.counts))
+	for name := range inv.counts {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if inv.counts[a] != inv.counts[b] {
+			return inv.counts[b] - inv.counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
+
+// WriteEndpoints 以 JSON Lines 格式输出每个接口的参数
+func (inv *Inventory) WriteEndpoints(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, ep := range inv.Endpoints() {
+		if err := encoder.Encode(ep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteWordlist 逐行输出参数名
+func (inv *Inventory) WriteWordlist(w io.Writer) error {
+	for _, name := range inv.Wordlist() {
+		if _, err := io.WriteString(w, name+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryParams 返回查询字符串中的参数名，保持出现的顺序
+func QueryParams(query string) []string {
+	var names []string
+	for _, pair := range strings.FieldsFunc(query, func(r rune) bool { return r == '&' || r == ';' }) {
+		name, _, _ := strings.Cut(pair, "=")
+		if n, err := url.QueryUnescape(name); err == nil {
+			name = n
+		}
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// JSONKeys 返回 JSON 对象中的字段名，包括嵌套对象和对象数组中的字段
+func JSONKeys(data []byte) ([]string, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return nil, false
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+	var names []string
+	collectKeys(v, 0, &names)
+	return names, true
+}
+
+func collectKeys(v any, depth int, names *[]string) {
+	if depth >= maxJSONDepth {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !slices.Contains(*names, k) {
+				*names = append(*names, k)
+			}
+			collectKeys(v[k], depth+1, names)
+		}
+	case []any:
+		// 数组中的对象通常结构相同，只取前几个
+		for _, item := range v[:min(len(v), 3)] {
+			collectKeys(item, depth+1, names)
+		}
+	}
+}
+
+// endpointKey 去掉 URL 的查询字符串和片段
+func endpointKey(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.RawQuery, u.Fragment, u.RawFragment = "", "", ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
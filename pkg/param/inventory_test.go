@@ -0,0 +1,59 @@
+package param
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	inv := New()
+	inv.AddURL("GET", "http://example.com/search?q=a&page=1#top")
+	inv.AddURL("get", "http://example.com/search?q=b&sort=asc")
+	inv.Add("POST", "http://example.com/login", LocationForm, "username", "password", "csrf")
+	inv.AddBody("POST", "http://example.com/api/users", []byte(`{"name":"a","profile":{"age":1},"tags":[{"id":1}]}`))
+	inv.AddBody("PUT", "http://example.com/api/users", []byte(`name=b&page=2`))
+	inv.AddResponse("GET", "http://example.com/api/items", []byte(`[{"id":1,"title":"x"}]`))
+	inv.AddURL("GET", "http://example.com/about")
+
+	endpoints := inv.Endpoints()
+	var urls []string
+	for _, ep := range endpoints {
+		urls = append(urls, ep.URL)
+	}
+	want := []string{"http://example.com/api/items", "http://example.com/api/users", "http://example.com/login", "http://example.com/search"}
+	if !slices.Equal(urls, want) {
+		t.Fatalf("got endpoints %v, want %v", urls, want)
+	}
+
+	users := endpoints[1]
+	if !slices.Equal(users.Methods, []string{"POST", "PUT"}) {
+		t.Errorf("wrong methods: %v", users.Methods)
+	}
+	var names []string
+	for _, p := range users.Params {
+		names = append(names, p.Name)
+	}
+	if !slices.Equal(names, []string{"name", "profile", "age", "tags", "id", "page"}) {
+		t.Errorf("wrong params: %v", names)
+	}
+	if !slices.Equal(users.Params[0].Locations, []string{LocationJSON, LocationBody}) {
+		t.Errorf("wrong locations: %v", users.Params[0].Locations)
+	}
+
+	// page 和 id 出现在两个接口中
+	wordlist := inv.Wordlist()
+	if !slices.Equal(wordlist[:2], []string{"id", "page"}) || len(wordlist) != 12 {
+		t.Errorf("wrong wordlist: %v", wordlist)
+	}
+
+	var buf bytes.Buffer
+	if err := inv.WriteEndpoints(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[3] != `{"url":"http://example.com/search","methods":["GET"],"params":[{"name":"q","locations":["query"]},{"name":"page","locations":["query"]},{"name":"sort","locations":["query"]}]}` {
+		t.Errorf("wrong endpoints output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ModePassive 只发送 GET、HEAD、OPTIONS 请求
+	ModePassive = "passive"
+	// ModeActive 发送允许列表中的请求
+	ModeActive = "active"
+	// ModeDryRun 记录所有请求但不发送
+	ModeDryRun = "dry-run"
+)
+
+var (
+	safeMethods = []string{"GET", "HEAD", "OPTIONS"}
+	// 默认不包括 DELETE
+	defaultActiveMethods = []string{"GET", "HEAD", "OPTIONS", "POST", "PUT", "PATCH"}
+)
+
+// MethodPolicy 决定哪些 HTTP 方法的请求可以发送
+type MethodPolicy struct {
+	Mode    string
+	Methods []string
+}
+
+// New 根据模式和允许的方法列表（逗号分隔，只在 active 模式下使用）创建策略
+func New(mode, methods string) (*MethodPolicy, error) {
+	p := &MethodPolicy{Mode: mode}
+	switch mode {
+	case ModePassive:
+		p.Methods = safeMethods
+	case ModeActive:
+		p.Methods = defaultActiveMethods
+		if methods != "" {
+			p.Methods = nil
+			for _, m := range strings.Split(methods, ",") {
+				if m = strings.ToUpper(strings.TrimSpace(m)); m != "" {
+					p.Methods = append(p.Methods, m)
+				}
+			}
+		}
+	case ModeDryRun:
+	default:
+		return nil, fmt.Errorf("unknown method policy: %s", mode)
+	}
+	return p, nil
+}
+
+// Allow 判断是否可以发送该方法的请求
+func (p *MethodPolicy) Allow(method string) bool {
+	if p.Mode == ModeDryRun {
+		return false
+	}
+	return slices.Contains(p.Methods, strings.ToUpper(method))
+}
+
+// Reason 返回请求被跳过的原因
+func (p *MethodPolicy) Reason(method string) string {
+	if p.Mode == ModeDryRun {
+		return "dry run"
+	}
+	return fmt.Sprintf("method %s is not allowed in %s mode", strings.ToUpper(method), p.Mode)
+}
@@ -0,0 +1,28 @@
+package policy
+
+import "testing"
+
+func TestMethodPolicy(t *testing.T) {
+	passive, _ := New(ModePassive, "POST")
+	if !passive.Allow("get") || passive.Allow("POST") {
+		t.Error("passive policy should only allow safe methods")
+	}
+
+	active, _ := New(ModeActive, "")
+	if !active.Allow("POST") || active.Allow("DELETE") {
+		t.Error("active policy should allow POST but not DELETE by default")
+	}
+	custom, _ := New(ModeActive, "get, delete")
+	if !custom.Allow("DELETE") || custom.Allow("POST") {
+		t.Error("active policy should follow the allowlist")
+	}
+
+	dryRun, _ := New(ModeDryRun, "")
+	if dryRun.Allow("GET") {
+		t.Error("dry run policy should not allow any request")
+	}
+
+	if _, err := New("aggressive", ""); err == nil {
+		t.Error("unknown mode should return an error")
+	}
+}
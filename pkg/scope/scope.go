@@ -0,0 +1,208 @@
+package scope
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule 是一条作用域规则，所有设置了的字段都匹配时规则才匹配
+type Rule struct {
+	Host   string `yaml:"host"`   // 主机名通配符，如 *.example.com
+	CIDR   string `yaml:"cidr"`   // IP 范围，如 10.0.0.0/8，只匹配以 IP 表示的主机
+	Port   int    `yaml:"port"`   // 端口，未指定时使用协议的默认端口
+	Scheme string `yaml:"scheme"` // http 或 https
+	Path   string `yaml:"path"`   // 路径前缀
+	Regex  string `yaml:"regex"`  // 匹配完整 URL 的正则表达式
+
+	network *net.IPNet
+	regex   *regexp.Regexp
+}
+
+// Config 是作用域配置文件的格式，支持 YAML 和 JSON
+type Config struct {
+	Include    []*Rule  `yaml:"include"`
+	Exclude    []*Rule  `yaml:"exclude"`
+	NeverVisit []string `yaml:"never_visit"` // 任何情况下都不访问的路径，支持前缀和通配符，如 /logout、/admin/*/delete
+}
+
+type Scope struct {
+	include    []*Rule
+	exclude    []*Rule
+	neverVisit []string
+}
+
+// Load 从 YAML 或 JSON 文件中加载作用域配置
+func Load(file string) (*Scope, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	// JSON 是 YAML 的子集，可以直接用 YAML 解析
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid scope file: %w", err)
+	}
+	return New(&config)
+}
+
+func New(config *Config) (*Scope, error) {
+	for _, rules := range [][]*Rule{config.Include, config.Exclude} {
+		for _, r := range rules {
+			if err := r.compile(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &Scope{
+		include:    config.Include,
+		exclude:    config.Exclude,
+		neverVisit: config.NeverVisit,
+	}, nil
+}
+
+// AddNeverVisit 添加不访问的路径
+func (s *Scope) AddNeverVisit(paths ...string) {
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p != "" {
+			s.neverVisit = append(s.neverVisit, p)
+		}
+	}
+}
+
+// HasIncludes 判断是否设置了 include 规则，设置后将替代默认的按目标主机划分的作用域
+func (s *Scope) HasIncludes() bool {
+	return len(s.include) > 0
+}
+
+// Included 判断 URL 是否匹配任意一条 include 规则
+func (s *Scope) Included(u *url.URL) bool {
+	for _, r := range s.include {
+		if r.Match(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded 判断 URL 是否匹配 exclude 规则或者是不访问的路径
+func (s *Scope) Excluded(u *url.URL) bool {
+	for _, p := range s.neverVisit {
+		if matchPath(p, u.Path) {
+			return true
+		}
+	}
+	for _, r := range s.exclude {
+		if r.Match(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed 判断 URL 是否允许访问
+func (s *Scope) Allowed(u *url.URL) bool {
+	if s.Excluded(u) {
+		return false
+	}
+	return !s.HasIncludes() || s.Included(u)
+}
+
+// AllowedURL 与 Allowed 相同，但接收字符串形式的 URL
+func (s *Scope) AllowedURL(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return s.Allowed(u)
+}
+
+func (r *Rule) compile() error {
+	if r.CIDR != "" {
+		_, network, err := net.ParseCIDR(r.CIDR)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR in scope rule: %s", r.CIDR)
+		}
+		r.network = network
+	}
+	if r.Regex != "" {
+		regex, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex in scope rule: %s", r.Regex)
+		}
+		r.regex = regex
+	}
+	if r.Host != "" {
+		if _, err := path.Match(r.Host, ""); err != nil {
+			return fmt.Errorf("invalid host pattern in scope rule: %s", r.Host)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) Match(u *url.URL) bool {
+	hostname := strings.ToLower(u.Hostname())
+	if r.Host != "" {
+		if ok, _ := path.Match(strings.ToLower(r.Host), hostname); !ok {
+			return false
+		}
+	}
+	if r.network != nil {
+		ip := net.ParseIP(hostname)
+		if ip == nil || !r.network.Contains(ip) {
+			return false
+		}
+	}
+	if r.Scheme != "" && !strings.EqualFold(r.Scheme, u.Scheme) {
+		return false
+	}
+	if r.Port != 0 && r.Port != port(u) {
+		return false
+	}
+	if r.Path != "" && !strings.HasPrefix(u.Path, r.Path) {
+		return false
+	}
+	if r.regex != nil && !r.regex.MatchString(u.String()) {
+		return false
+	}
+	return true
+}
+
+func port(u *url.URL) int {
+	if p := u.Port(); p != "" {
+		n, _ := strconv.Atoi(p)
+		return n
+	}
+	switch u.Scheme {
+	case "https":
+		return 443
+	case "http":
+		return 80
+	default:
+		return 0
+	}
+}
+
+// matchPath 匹配不访问的路径，包含通配符时按通配符匹配，否则按前缀匹配
+func matchPath(pattern, p string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		// 通配符同样作为前缀使用，/admin/*/delete 也匹配 /admin/1/delete/confirm
+		for prefix := p; prefix != "/" && prefix != "."; prefix = path.Dir(prefix) {
+			if ok, _ := path.Match(pattern, prefix); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.HasPrefix(p, pattern)
+}
@@ -0,0 +1,67 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	config := `
+include:
+  - host: "*.example.com"
+    scheme: https
+  - cidr: 10.0.0.0/8
+    port: 8080
+exclude:
+  - path: /static/
+  - regex: "\\.pdf$"
+never_visit:
+  - /logout
+  - /admin/*/delete
+`
+	file := filepath.Join(t.TempDir(), "scope.yaml")
+	os.WriteFile(file, []byte(config), 0644)
+
+	s, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]bool{
+		"https://www.example.com/":               true,
+		"http://www.example.com/":                false,
+		"https://example.org/":                   false,
+		"http://10.1.2.3:8080/api":               true,
+		"http://10.1.2.3/api":                    false,
+		"https://www.example.com/static/a.js":    false,
+		"https://www.example.com/doc/x.pdf":      false,
+		"https://www.example.com/logout?next=/":  false,
+		"https://www.example.com/admin/1/delete": false,
+		"https://www.example.com/admin/1/edit":   true,
+	}
+	for link, expected := range cases {
+		if s.AllowedURL(link) != expected {
+			t.Errorf("AllowedURL(%q) should be %v", link, expected)
+		}
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scope.json")
+	os.WriteFile(file, []byte(`{"exclude": [{"host": "cdn.example.com"}]}`), 0644)
+
+	s, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.HasIncludes() {
+		t.Error("scope should not have include rules")
+	}
+	if s.AllowedURL("https://cdn.example.com/a.js") {
+		t.Error("excluded host should not be allowed")
+	}
+	if !s.AllowedURL("https://www.example.com/") {
+		t.Error("host not excluded should be allowed")
+	}
+}
@@ -0,0 +1,223 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 规则的严重程度
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// Rule 是一条敏感信息检测规则
+type Rule struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description"`
+	Severity    string   `yaml:"severity"`
+	Regex       string   `yaml:"regex"`
+	Group       int      `yaml:"group"`     // 敏感信息所在的捕获组，默认为整个匹配
+	Entropy     float64  `yaml:"entropy"`   // 敏感信息的最小香农熵，用于排除 xxxx、example 等占位值
+	Keywords    []string `yaml:"keywords"`  // 内容中包含任意一个关键字（不区分大小写）时才使用正则匹配
+	Allowlist   []string `yaml:"allowlist"` // 匹配这些正则的结果不输出
+
+	regex     *regexp.Regexp
+	allowlist []*regexp.Regexp
+}
+
+// Config 是规则文件的格式，支持 YAML 和 JSON。文件中的规则与内置规则 ID 相同时替换内置规则
+type Config struct {
+	Rules   []*Rule  `yaml:"rules"`
+	Disable []string `yaml:"disable"` // 禁用的规则 ID
+}
+
+// 内置规则
+var defaultRules = []*Rule{
+	{
+		ID:          "aws-access-key-id",
+		Description: "AWS access key ID",
+		Severity:    SeverityHigh,
+		Regex:       `\b((?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16})\b`,
+		Group:       1,
+		Entropy:     3,
+	},
+	{
+		ID:          "aws-secret-access-key",
+		Description: "AWS secret access key",
+		Severity:    SeverityHigh,
+		Regex:       `(?i)aws.{0,20}?(?:secret|private)?.{0,20}?key["']?\s*[:=]\s*["']([0-9a-zA-Z/+]{40})["']`,
+		Group:       1,
+		Entropy:     4,
+		Keywords:    []string{"aws"},
+	},
+	{
+		ID:          "aliyun-access-key-id",
+		Description: "Alibaba Cloud AccessKey ID",
+		Severity:    SeverityHigh,
+		Regex:       `\b(LTAI[0-9A-Za-z]{12,20})\b`,
+		Group:       1,
+		Entropy:     3,
+	},
+	{
+		ID:          "tencent-secret-id",
+		Description: "Tencent Cloud SecretId",
+		Severity:    SeverityHigh,
+		Regex:       `\b(AKID[0-9A-Za-z]{13,40})\b`,
+		Group:       1,
+		Entropy:     3,
+	},
+	{
+		ID:          "google-api-key",
+		Description: "Google API key",
+		Severity:    SeverityMedium,
+		Regex:       `\bAIza[0-9A-Za-z_\-]{35}`,
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Severity:    SeverityHigh,
+		Regex:       `\bgh[pousr]_[0-9A-Za-z]{36,255}\b`,
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Severity:    SeverityHigh,
+		Regex:       `\bxox[baprs]-[0-9A-Za-z\-]{10,72}\b`,
+	},
+	{
+		ID:          "slack-webhook",
+		Description: "Slack incoming webhook",
+		Severity:    SeverityMedium,
+		Regex:       `https://hooks\.slack\.com/services/T[0-9A-Za-z_]+/B[0-9A-Za-z_]+/[0-9A-Za-z_]+`,
+	},
+	{
+		ID:          "stripe-secret-key",
+		Description: "Stripe secret key",
+		Severity:    SeverityHigh,
+		Regex:       `\b[rs]k_live_[0-9a-zA-Z]{24,99}\b`,
+	},
+	{
+		ID:          "private-key",
+		Description: "Private key",
+		Severity:    SeverityHigh,
+		Regex:       `-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`,
+	},
+	{
+		ID:          "jwt",
+		Description: "JSON Web Token",
+		Severity:    SeverityMedium,
+		Regex:       `\beyJ[0-9A-Za-z_\-]{8,}\.eyJ[0-9A-Za-z_\-]{8,}\.[0-9A-Za-z_\-]{8,}`,
+	},
+	{
+		ID:          "generic-secret",
+		Description: "Hard-coded password, token or API key",
+		Severity:    SeverityMedium,
+		Regex:       `(?i)["']?(?:api[_-]?key|api[_-]?secret|app[_-]?secret|client[_-]?secret|access[_-]?token|secret[_-]?key|password|passwd)["']?\s*[:=]\s*["']([^"'\s]{8,64})["']`,
+		Group:       1,
+		Entropy:     3.5,
+		Keywords:    []string{"key", "secret", "token", "passw"},
+	},
+	{
+		ID:          "internal-ip",
+		Description: "Private IPv4 address",
+		Severity:    SeverityLow,
+		Regex:       `(?:^|[^\w.])((?:10\.(?:25[0-5]|2[0-4]\d|1?\d?\d)|172\.(?:1[6-9]|2\d|3[01])|192\.168)(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){2})(?:$|[^\w.])`,
+		Group:       1,
+	},
+	{
+		ID:          "internal-hostname",
+		Description: "Internal hostname",
+		Severity:    SeverityLow,
+		Regex:       `(?i)(?:["'@]|//)((?:[a-z0-9][a-z0-9\-]*\.)+(?:internal|intranet|corp|lan|local))(?::\d+)?(?:["'/]|$)`,
+		Group:       1,
+	},
+	{
+		ID:          "email",
+		Description: "Email address",
+		Severity:    SeverityLow,
+		Regex:       `\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`,
+		Allowlist:   []string{`@(?:example\.(?:com|org|net)|domain\.com|email\.com)$`, `@\d+x\.`, `\.(?:png|jpe?g|gif|svg|webp)$`},
+	},
+}
+
+// DefaultRules 返回内置规则
+func DefaultRules() []*Rule {
+	rules := make([]*Rule, 0, len(defaultRules))
+	for _, r := range defaultRules {
+		clone := *r
+		rules = append(rules, &clone)
+	}
+	return rules
+}
+
+// Load 从 YAML 或 JSON 文件中加载规则，并与内置规则合并
+func Load(file string) ([]*Rule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	// JSON 是 YAML 的子集，可以直接用 YAML 解析
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid secret rule file: %w", err)
+	}
+	return Merge(DefaultRules(), &config), nil
+}
+
+// Merge 将配置中的规则合并到 rules 中，并去掉禁用的规则
+func Merge(rules []*Rule, config *Config) []*Rule {
+	for _, r := range config.Rules {
+		i := slices.IndexFunc(rules, func(o *Rule) bool { return o.ID == r.ID })
+		if i >= 0 {
+			rules[i] = r
+		} else {
+			rules = append(rules, r)
+		}
+	}
+	return slices.DeleteFunc(rules, func(r *Rule) bool {
+		return slices.Contains(config.Disable, r.ID)
+	})
+}
+
+func (r *Rule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("secret rule without id: %s", r.Regex)
+	}
+	re, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex in secret rule %s: %w", r.ID, err)
+	}
+	if r.Group < 0 || r.Group > re.NumSubexp() {
+		return fmt.Errorf("invalid group in secret rule %s: %d", r.ID, r.Group)
+	}
+	r.regex = re
+	r.allowlist = nil
+	for _, a := range r.Allowlist {
+		re, err := regexp.Compile(a)
+		if err != nil {
+			return fmt.Errorf("invalid allowlist in secret rule %s: %w", r.ID, err)
+		}
+		r.allowlist = append(r.allowlist, re)
+	}
+	for i, k := range r.Keywords {
+		r.Keywords[i] = strings.ToLower(k)
+	}
+	if r.Severity == "" {
+		r.Severity = SeverityMedium
+	}
+	return nil
+}
+
+// allowed 判断匹配结果是否在白名单中
+func (r *Rule) allowed(match string) bool {
+	return slices.ContainsFunc(r.allowlist, func(re *regexp.Regexp) bool {
+		return re.MatchString(match)
+	})
+}
@@ -0,0 +1,146 @@
+package secret
+
+import (
+	"bytes"
+	"math"
+	"sync"
+)
+
+// Finding 是在响应内容中发现的一条敏感信息
+type Finding struct {
+	Rule    *Rule
+	URL     string
+	Match   string
+	Line    int // 从 1 开始的行号
+	Column  int // 从 1 开始的列号（字节）
+	Offset  int // 从 0 开始的字节偏移
+	Entropy float64
+}
+
+// Scanner 使用规则检测内容中的敏感信息，同一规则匹配到的相同内容只报告第一次出现的位置
+type Scanner struct {
+	rules []*Rule
+
+	mutex sync.Mutex
+	seen  map[string]bool
+}
+
+// NewScanner 创建 Scanner，rules 为空时使用内置规则
+func NewScanner(rules []*Rule) (*Scanner, error) {
+	if rules == nil {
+		rules = DefaultRules()
+	}
+	for _, r := range rules {
+		if err := r.compile(); err != nil {
+			return nil, err
+		}
+	}
+	return &Scanner{rules: rules, seen: make(map[string]bool)}, nil
+}
+
+// Scan 检测 content 中的敏感信息，url 是内容所在的位置
+func (s *Scanner) Scan(url string, content []byte) []*Finding {
+	var findings []*Finding
+	var lower []byte
+	lines := newLineIndex(content)
+
+	for _, r := range s.rules {
+		if len(r.Keywords) > 0 {
+			if lower == nil {
+				lower = bytes.ToLower(content)
+			}
+			if !containsAny(lower, r.Keywords) {
+				continue
+			}
+		}
+		for _, m := range r.regex.FindAllSubmatchIndex(content, -1) {
+			start, end := m[2*r.Group], m[2*r.Group+1]
+			if start < 0 {
+				continue
+			}
+			match := string(content[start:end])
+			entropy := shannonEntropy(match)
+			if entropy < r.Entropy || r.allowed(match) || !s.visit(r.ID, match) {
+				continue
+			}
+			line, column := lines.position(start)
+			findings = append(findings, &Finding{
+				Rule:    r,
+				URL:     url,
+				Match:   match,
+				Line:    line,
+				Column:  column,
+				Offset:  start,
+				Entropy: entropy,
+			})
+		}
+	}
+	return findings
+}
+
+// visit 记录规则匹配到的内容，返回是否为第一次出现
+func (s *Scanner) visit(id, match string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := id + "\x00" + match
+	if s.seen[key] {
+		return false
+	}
+	s.seen[key] = true
+	return true
+}
+
+func containsAny(content []byte, keywords []string) bool {
+	for _, k := range keywords {
+		if bytes.Contains(content, []byte(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy 计算字符串每个字符的香农熵
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// lineIndex 记录每一行的起始偏移，用于将偏移转换为行号和列号
+type lineIndex []int
+
+func newLineIndex(content []byte) lineIndex {
+	index := lineIndex{0}
+	for i, c := range content {
+		if c == '\n' {
+			index = append(index, i+1)
+		}
+	}
+	return index
+}
+
+func (index lineIndex) position(offset int) (line, column int) {
+	// 二分查找最后一个不大于 offset 的行首
+	lo, hi := 0, len(index)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if index[mid] <= offset {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo + 1, offset - index[lo] + 1
+}
@@ -1,7 +1,6 @@
 package util
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"net/url"
@@ -13,6 +12,11 @@ import (
 	"github.com/gocolly/colly/v2"
 )
 
+var (
+	swaggerFlagRegex     = regexp.MustCompile(`"(swagger|openapi)"\s*:\s*"\d`)
+	swaggerYAMLFlagRegex = regexp.MustCompile(`(?m)^(swagger|openapi)\s*:\s*["']?\d`)
+)
+
 func FixURL(base *url.URL, path string) string {
 	// 处理 base 和 path 中重叠的路径
 	// TODO: 当前实现不够完善，待优化
@@ -98,13 +102,25 @@ func StripQueryParams(u *url.URL) string {
 	return stripped
 }
 
+// IsSwaggerSchema 判断响应是否为 JSON 或 YAML 格式的 Swagger 2.0 或 OpenAPI 3.x 文档。
+// YAML 文档的 Content-Type 没有统一的标准，也可以根据扩展名判断
 func IsSwaggerSchema(resp *colly.Response) bool {
 	ct := resp.Headers.Get("Content-Type")
-	flag := []byte(`"swagger":`)
-	return strings.HasPrefix(ct, "application/json") && bytes.Contains(resp.Body, flag)
+	if strings.HasPrefix(ct, "application/json") || strings.HasPrefix(ct, "application/vnd.oai.openapi+json") {
+		return swaggerFlagRegex.Match(resp.Body)
+	}
+	if ext := GetExtension(resp.Request.URL.String()); strings.Contains(ct, "yaml") || strings.HasPrefix(ct, "application/vnd.oai.openapi") || ext == ".yaml" || ext == ".yml" {
+		return swaggerYAMLFlagRegex.Match(resp.Body)
+	}
+	return false
 }
 
 func IsScriptOrJSON(link string) bool {
 	ext := GetExtension(link)
-	return ext == ".js" || ext == ".ts" || ext == ".json" || strings.HasSuffix(link, "swagger-resources")
+	return ext == ".js" || ext == ".mjs" || ext == ".ts" || ext == ".json" || strings.HasSuffix(link, "swagger-resources")
+}
+
+// IsSourceMap 判断链接是否为 source map 文件
+func IsSourceMap(link string) bool {
+	return GetExtension(link) == ".map"
 }
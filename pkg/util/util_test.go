@@ -2,8 +2,11 @@ package util
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
+
+	"github.com/gocolly/colly/v2"
 )
 
 func TestFixURL(t *testing.T) {
@@ -60,3 +63,29 @@ func ExampleGetExtension() {
 	// .jsp
 	// .php
 }
+
+func TestIsSwaggerSchema(t *testing.T) {
+	tests := []struct {
+		link string
+		ct   string
+		body string
+		want bool
+	}{
+		{"http://example.com/v2/api-docs", "application/json", `{"swagger":"2.0","paths":{}}`, true},
+		{"http://example.com/openapi.yaml", "text/plain", "openapi: 3.0.1\npaths: {}\n", true},
+		{"http://example.com/v3/api-docs", "application/vnd.oai.openapi", "openapi: '3.1.0'\n", true},
+		{"http://example.com/config.yaml", "text/plain", "name: test\n", false},
+		{"http://example.com/swagger-ui-bundle.js", "application/javascript", `{"swagger":"2.0"}`, false},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.link)
+		resp := &colly.Response{
+			Request: &colly.Request{URL: u},
+			Headers: &http.Header{"Content-Type": []string{tt.ct}},
+			Body:    []byte(tt.body),
+		}
+		if IsSwaggerSchema(resp) != tt.want {
+			t.Errorf("IsSwaggerSchema(%s) should be %v", tt.link, tt.want)
+		}
+	}
+}